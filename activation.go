@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// listenFdsStart is the first file descriptor passed by systemd.  Tests
+// pass other descriptors.
+var listenFdsStart = 3
+
+// activationListeners returns the sockets passed by systemd socket
+// activation and their names from FileDescriptorName=.  The variables
+// are removed from the environment, so that hooks do not inherit them.
+func activationListeners(firstFd int) ([]net.Listener, []string, error) {
+	pid, fds, names := os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS"), os.Getenv("LISTEN_FDNAMES")
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	if fds == "" || pid != strconv.Itoa(os.Getpid()) {
+		return nil, nil, nil
+	}
+	n, err := strconv.Atoi(fds)
+	if err != nil || n < 0 {
+		return nil, nil, fmt.Errorf("invalid LISTEN_FDS %q", fds)
+	}
+
+	listeners := []net.Listener{}
+	nameList := strings.Split(names, ":")
+	for fd := firstFd; fd < firstFd+n; fd++ {
+		syscall.CloseOnExec(fd)
+		f := os.NewFile(uintptr(fd), "LISTEN_FD_"+strconv.Itoa(fd))
+		l, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, nil, fmt.Errorf("file descriptor %d: %v", fd, err)
+		}
+		listeners = append(listeners, l)
+	}
+	for len(nameList) < len(listeners) {
+		nameList = append(nameList, "")
+	}
+	return listeners, nameList[:len(listeners)], nil
+}
+
+// port returns the port a listener is bound to.
+func port(l net.Listener) string {
+	_, p, _ := net.SplitHostPort(l.Addr().String())
+	return p
+}
+
+// listen returns the listeners for SMTP and HTTP.  Sockets passed by
+// systemd are used if their name is "smtp" or "http" or if they are
+// bound to -smtp-port or -http-port.  The other ports are bound here.
+func listen(config Config) (smtpListener net.Listener, httpListener net.Listener, err error) {
+	listeners, names, err := activationListeners(listenFdsStart)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, match := range []func(int) string{
+		func(i int) string { return names[i] },
+		func(i int) string {
+			switch port(listeners[i]) {
+			case config.SMTPPort:
+				return "smtp"
+			case config.HTTPPort:
+				return "http"
+			}
+			return ""
+		},
+	} {
+		for i, l := range listeners {
+			if l == nil {
+				continue
+			}
+			switch match(i) {
+			case "smtp":
+				if smtpListener == nil {
+					smtpListener, listeners[i] = l, nil
+				}
+			case "http":
+				if httpListener == nil {
+					httpListener, listeners[i] = l, nil
+				}
+			}
+		}
+	}
+	for i, l := range listeners {
+		if l != nil {
+			logger.Warn("Ignoring socket passed by systemd", "name", names[i], "address", l.Addr())
+			l.Close()
+		}
+	}
+
+	if smtpListener == nil {
+		if smtpListener, err = net.Listen("tcp", config.Host+":"+config.SMTPPort); err != nil {
+			if httpListener != nil {
+				httpListener.Close()
+			}
+			return nil, nil, err
+		}
+	}
+	if httpListener == nil {
+		if httpListener, err = net.Listen("tcp", config.Host+":"+config.HTTPPort); err != nil {
+			smtpListener.Close()
+			return nil, nil, err
+		}
+	}
+	return smtpListener, httpListener, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+)
+
+func TestActivation(t *testing.T) {
+	defer func(start int) { listenFdsStart = start }(listenFdsStart)
+	listenFdsStart = 200
+
+	addresses := []string{}
+	for i := 0; i < 3; i++ {
+		l, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := l.(*net.TCPListener).File()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := syscall.Dup2(int(f.Fd()), listenFdsStart+i); err != nil {
+			t.Fatal(err)
+		}
+		addresses = append(addresses, l.Addr().String())
+		f.Close()
+		l.Close()
+	}
+
+	os.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+	os.Setenv("LISTEN_FDS", "3")
+	os.Setenv("LISTEN_FDNAMES", "unknown:http")
+	var config Config
+	config.Host = "localhost"
+	_, config.SMTPPort, _ = net.SplitHostPort(addresses[2])
+	config.HTTPPort = "0"
+
+	smtpListener, httpListener, err := listen(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer smtpListener.Close()
+	defer httpListener.Close()
+	if smtpListener.Addr().String() != addresses[2] || httpListener.Addr().String() != addresses[1] {
+		t.Errorf("Unexpected listeners %v and %v for %v", smtpListener.Addr(), httpListener.Addr(), addresses)
+	}
+	if _, ok := os.LookupEnv("LISTEN_FDS"); ok {
+		t.Error("Environment not cleared")
+	}
+	if _, err := net.Dial("tcp", addresses[0]); err == nil {
+		t.Error("Unknown socket not closed")
+	}
+
+	// Without activation, both ports are bound.
+	config.SMTPPort = "0"
+	smtpListener, httpListener, err = listen(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	smtpListener.Close()
+	httpListener.Close()
+
+	os.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+	os.Setenv("LISTEN_FDS", "x")
+	if _, _, err := listen(config); err == nil {
+		t.Error("Invalid LISTEN_FDS accepted")
+	}
+}
@@ -0,0 +1,729 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"./model"
+)
+
+type command struct {
+	usage  string
+	action func(db model.Database, id int) error
+}
+
+var commands = map[string]command{
+	"archive": {"Move voicemails to the archive",
+		model.Database.ArchiveVoicemail},
+	"unarchive": {"Move archived voicemails back to the inbox",
+		model.Database.UnarchiveVoicemail},
+	"delete": {"Move voicemails to the trash",
+		model.Database.DeleteVoicemail},
+	"restore": {"Restore voicemails from the trash",
+		model.Database.RestoreVoicemail},
+	"star": {"Mark voicemails as important",
+		model.Database.StarVoicemail},
+	"unstar": {"Remove the important mark from voicemails",
+		model.Database.UnstarVoicemail},
+	"mark-read": {"Mark voicemails as listened to",
+		model.Database.MarkRead},
+	"mark-unread": {"Mark voicemails as not listened to",
+		model.Database.MarkUnread},
+	"purge": {"Permanently remove voicemails and their audio files",
+		model.Database.PurgeVoicemail},
+}
+
+func printCommands() {
+	fmt.Fprintf(os.Stderr, "\nCommands (operate on voicemail ids):\n")
+	for _, name := range []string{"archive", "unarchive", "delete", "restore", "star", "unstar", "mark-read", "mark-unread", "purge"} {
+		fmt.Fprintf(os.Stderr, "  %s id...: %s\n", name, commands[name].usage)
+	}
+	fmt.Fprintf(os.Stderr, "\nOther commands (-h for their options):\n")
+	fmt.Fprintf(os.Stderr, "  list [words]: List voicemails\n")
+	fmt.Fprintf(os.Stderr, "  show id...: Show all details of voicemails\n")
+	fmt.Fprintf(os.Stderr, "  stats: Count voicemails by folder and mailbox\n")
+	fmt.Fprintf(os.Stderr, "  export dir: Copy voicemails with their details to a directory\n")
+	fmt.Fprintf(os.Stderr, "  import [-dry-run] path...: Add exported voicemails or voicemail emails from .eml files, mbox files or Maildirs\n")
+	fmt.Fprintf(os.Stderr, "  empty-trash: Purge all voicemails in the trash\n")
+	fmt.Fprintf(os.Stderr, "  users list|add|mailboxes|passwd|delete [name] [mailbox...]: Manage web interface users\n")
+	fmt.Fprintf(os.Stderr, "  tokens list|add|revoke ...: Manage API tokens\n")
+	fmt.Fprintf(os.Stderr, "  deliveries [count]: Show the log of notifications sent for new voicemails\n")
+}
+
+// commandFlags returns the flags of a command.  -json selects JSON
+// instead of table output.
+func commandFlags(name string, usage string) (*flag.FlagSet, *bool) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s\n", usage)
+		fs.PrintDefaults()
+	}
+	return fs, fs.Bool("json", false, "Print JSON instead of a table")
+}
+
+// printJSON writes v as indented JSON to stdout.
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// printTable writes rows aligned in columns below a header, if any, to
+// stdout.
+func printTable(header []string, rows [][]string) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	if header != nil {
+		fmt.Fprintln(w, strings.Join(header, "\t"))
+	}
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// readPassword prompts for a password on stderr and reads it from
+// stdin, without echoing it if stdin is a terminal.
+func readPassword(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		stty := func(arg string) {
+			cmd := exec.Command("stty", arg)
+			cmd.Stdin = os.Stdin
+			cmd.Run()
+		}
+		stty("-echo")
+		defer fmt.Fprintln(os.Stderr)
+		defer stty("echo")
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+const usersUsage = `Usage: users list [-json]
+       users add name [mailbox...]
+       users mailboxes name mailbox...
+       users passwd|delete name`
+
+type jsonUser struct {
+	Name      string   `json:"name"`
+	Mailboxes []string `json:"mailboxes"`
+}
+
+func runUsersCommand(db model.Database, args []string) int {
+	if len(args) > 0 && args[0] == "list" {
+		fs, asJSON := commandFlags("users list", "users list [-json]")
+		if err := fs.Parse(args[1:]); err != nil || fs.NArg() > 0 {
+			fmt.Fprintln(os.Stderr, usersUsage)
+			return 2
+		}
+		users, err := db.GetUsers()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		list, rows := []jsonUser{}, [][]string{}
+		for _, user := range users {
+			list = append(list, jsonUser{user.Name, user.Mailboxes})
+			rows = append(rows, []string{user.Name, strings.Join(user.Mailboxes, " ")})
+		}
+		if *asJSON {
+			err = printJSON(list)
+		} else {
+			err = printTable([]string{"NAME", "MAILBOXES"}, rows)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	if len(args) < 2 || (len(args) > 2 && args[0] != "add" && args[0] != "mailboxes") {
+		fmt.Fprintln(os.Stderr, usersUsage)
+		return 2
+	}
+
+	var err error
+	switch name, mailboxes := args[1], args[2:]; args[0] {
+	case "add", "passwd":
+		var password string
+		if password, err = readPassword("Password for " + name + ": "); err != nil {
+			break
+		}
+		if args[0] == "add" {
+			if len(mailboxes) == 0 {
+				mailboxes = []string{model.AllMailboxes}
+			}
+			err = db.CreateUser(name, password, mailboxes)
+		} else {
+			err = db.SetPassword(name, password)
+		}
+	case "mailboxes":
+		if len(mailboxes) == 0 {
+			fmt.Fprintln(os.Stderr, usersUsage)
+			return 2
+		}
+		err = db.SetUserMailboxes(name, mailboxes)
+	case "delete":
+		err = db.DeleteUser(name)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown users command: %s\n", args[0])
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "users %s: %v\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+const tokensUsage = `Usage: tokens list [-json] [user]
+       tokens add user name read|write|admin|feed
+       tokens revoke id...`
+
+type jsonToken struct {
+	Id       int           `json:"id"`
+	User     string        `json:"user"`
+	Name     string        `json:"name"`
+	Scopes   []model.Scope `json:"scopes"`
+	Created  time.Time     `json:"created"`
+	LastUsed *time.Time    `json:"last_used,omitempty"`
+}
+
+func runTokensCommand(db model.Database, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, tokensUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "list":
+		fs, asJSON := commandFlags("tokens list", "tokens list [-json] [user]")
+		if err := fs.Parse(args[1:]); err != nil || fs.NArg() > 1 {
+			fmt.Fprintln(os.Stderr, tokensUsage)
+			return 2
+		}
+		tokens, err := db.GetTokens(fs.Arg(0))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		list, rows := []jsonToken{}, [][]string{}
+		for _, token := range tokens {
+			t := jsonToken{token.Id, token.User, token.Name, token.Scopes, token.Created, nil}
+			lastUsed := "never"
+			if !token.LastUsed.IsZero() {
+				t.LastUsed = &token.LastUsed
+				lastUsed = token.LastUsed.Format("2006-01-02 15:04")
+			}
+			scopes := make([]string, len(token.Scopes))
+			for i, scope := range token.Scopes {
+				scopes[i] = string(scope)
+			}
+			list = append(list, t)
+			rows = append(rows, []string{strconv.Itoa(token.Id), token.User, token.Name,
+				strings.Join(scopes, ","), lastUsed})
+		}
+		if *asJSON {
+			err = printJSON(list)
+		} else {
+			err = printTable([]string{"ID", "USER", "NAME", "SCOPES", "LAST USED"}, rows)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+
+	case "add":
+		if len(args) != 4 {
+			fmt.Fprintln(os.Stderr, tokensUsage)
+			return 2
+		}
+		scopes, err := model.ParseScopes(strings.Split(args[3], ","))
+		if err == nil {
+			var secret string
+			if _, secret, err = db.CreateToken(args[1], args[2], scopes); err == nil {
+				fmt.Println(secret)
+				return 0
+			}
+		}
+		fmt.Fprintf(os.Stderr, "tokens add: %v\n", err)
+		return 1
+
+	case "revoke":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, tokensUsage)
+			return 2
+		}
+		status := 0
+		for _, arg := range args[1:] {
+			id, err := strconv.Atoi(arg)
+			if err == nil {
+				err = db.RevokeToken(id, "")
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "tokens revoke %s: %v\n", arg, err)
+				status = 1
+			}
+		}
+		return status
+	}
+
+	fmt.Fprintf(os.Stderr, "Unknown tokens command: %s\n", args[0])
+	return 2
+}
+
+type jsonDelivery struct {
+	Voicemail int       `json:"voicemail"`
+	Channel   string    `json:"channel"`
+	Target    string    `json:"target"`
+	Attempt   int       `json:"attempt"`
+	Success   bool      `json:"success"`
+	Error     string    `json:"error,omitempty"`
+	Date      time.Time `json:"date"`
+}
+
+func runDeliveriesCommand(db model.Database, args []string) int {
+	fs, asJSON := commandFlags("deliveries", "deliveries [-json] [count]")
+	if err := fs.Parse(args); err != nil || fs.NArg() > 1 {
+		fmt.Fprintln(os.Stderr, "Usage: deliveries [-json] [count]")
+		return 2
+	}
+	limit := 20
+	if fs.NArg() == 1 {
+		var err error
+		if limit, err = strconv.Atoi(fs.Arg(0)); err != nil {
+			fmt.Fprintf(os.Stderr, "deliveries: invalid count %q\n", fs.Arg(0))
+			return 2
+		}
+	}
+
+	deliveries, err := db.GetDeliveries(limit)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	list, rows := []jsonDelivery{}, [][]string{}
+	for _, delivery := range deliveries {
+		status := "ok"
+		if !delivery.Success {
+			status = "failed: " + delivery.Error
+		}
+		list = append(list, jsonDelivery{delivery.Voicemail, delivery.Channel, delivery.Target,
+			delivery.Attempt, delivery.Success, delivery.Error, delivery.Date})
+		rows = append(rows, []string{delivery.Date.Format("2006-01-02 15:04:05"),
+			strconv.Itoa(delivery.Voicemail), delivery.Channel, delivery.Target,
+			strconv.Itoa(delivery.Attempt), status})
+	}
+	if *asJSON {
+		err = printJSON(list)
+	} else {
+		err = printTable([]string{"DATE", "VOICEMAIL", "CHANNEL", "TARGET", "ATTEMPT", "STATUS"}, rows)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// jsonVoicemail is a voicemail as printed by list and show and stored
+// by export.  File is the name of the MP3 file.
+type jsonVoicemail struct {
+	Id         int       `json:"id"`
+	Caller     string    `json:"caller"`
+	Called     string    `json:"called"`
+	Mailbox    string    `json:"mailbox,omitempty"`
+	Date       time.Time `json:"date"`
+	Duration   float64   `json:"duration"`
+	Read       bool      `json:"read"`
+	Starred    bool      `json:"starred"`
+	Archived   bool      `json:"archived"`
+	Deleted    bool      `json:"deleted"`
+	Notes      string    `json:"notes"`
+	Transcript string    `json:"transcript"`
+	Tags       []string  `json:"tags"`
+	File       string    `json:"file"`
+}
+
+func newJSONVoicemail(voicemail model.Voicemail, mailboxes model.Mailboxes) jsonVoicemail {
+	v := jsonVoicemail{
+		Id:         voicemail.Id,
+		Caller:     voicemail.Caller,
+		Called:     voicemail.Called,
+		Date:       voicemail.Date,
+		Duration:   voicemail.Duration.Seconds(),
+		Read:       voicemail.Read,
+		Starred:    voicemail.Starred,
+		Archived:   voicemail.Archived,
+		Deleted:    voicemail.Deleted,
+		Notes:      voicemail.Notes,
+		Transcript: voicemail.Transcript,
+		Tags:       voicemail.Tags,
+		File:       path.Base(voicemail.VoicemailPath),
+	}
+	if mailbox, ok := mailboxes.ForNumber(voicemail.Called); ok {
+		v.Mailbox = mailbox.Name
+	}
+	return v
+}
+
+// voicemail returns the voicemail without id and file.
+func (v jsonVoicemail) voicemail() model.Voicemail {
+	number := func(n string) string {
+		if n == model.UnknownNumber {
+			return ""
+		}
+		return n
+	}
+	return model.Voicemail{
+		Caller:     number(v.Caller),
+		Called:     number(v.Called),
+		Date:       v.Date,
+		Duration:   time.Duration(v.Duration * float64(time.Second)),
+		Read:       v.Read,
+		Starred:    v.Starred,
+		Archived:   v.Archived,
+		Deleted:    v.Deleted,
+		Notes:      v.Notes,
+		Transcript: v.Transcript,
+		Tags:       v.Tags,
+	}
+}
+
+// folderName returns the folder a voicemail is in.
+func folderName(voicemail model.Voicemail) string {
+	switch {
+	case voicemail.Deleted:
+		return "trash"
+	case voicemail.Archived:
+		return "archive"
+	}
+	return "inbox"
+}
+
+var commandFolders = map[string]model.Folder{
+	"inbox":   model.Inbox,
+	"archive": model.Archive,
+	"trash":   model.Trash,
+	"all":     model.AllFolders,
+}
+
+// filterFlags registers the options that select voicemails.  The
+// returned function builds the folder and filter after parsing, words
+// are searched for.
+func filterFlags(fs *flag.FlagSet, mailboxes model.Mailboxes, folder string) func(words []string) (model.Folder, model.Filter, error) {
+	folderFlag := fs.String("folder", folder, "Folder: inbox, archive, trash or all")
+	mailboxFlag := fs.String("mailbox", "", "Only voicemails in this mailbox")
+	caller := fs.String("caller", "", "Only voicemails from numbers containing this")
+	tags := fs.String("tags", "", "Only voicemails with all of these comma separated tags")
+	unread := fs.Bool("unread", false, "Only voicemails not listened to")
+	from := fs.String("from", "", "Only voicemails since this day (YYYY-MM-DD)")
+	until := fs.String("until", "", "Only voicemails until this day (YYYY-MM-DD)")
+
+	return func(words []string) (model.Folder, model.Filter, error) {
+		folder, ok := commandFolders[*folderFlag]
+		if !ok {
+			return 0, model.Filter{}, fmt.Errorf("unknown folder %q", *folderFlag)
+		}
+
+		filter := model.Filter{}
+		if *mailboxFlag != "" {
+			mailbox, ok := mailboxes.Get(*mailboxFlag)
+			if !ok {
+				return 0, model.Filter{}, fmt.Errorf("unknown mailbox %q", *mailboxFlag)
+			}
+			filter = mailboxes.Filter(mailbox)
+		}
+		filter.Text = model.MatchWords(strings.Join(words, " "))
+		filter.Caller = *caller
+		filter.Tags = model.ParseTags(*tags)
+		if *unread {
+			filter.Read = model.OnlyUnread
+		}
+		for _, date := range []struct {
+			value string
+			t     *time.Time
+			days  int
+		}{{*from, &filter.From, 0}, {*until, &filter.Until, 1}} {
+			if date.value == "" {
+				continue
+			}
+			t, err := time.ParseInLocation("2006-01-02", date.value, time.Local)
+			if err != nil {
+				return 0, model.Filter{}, fmt.Errorf("invalid date %q", date.value)
+			}
+			*date.t = t.AddDate(0, 0, date.days)
+		}
+		return folder, filter, nil
+	}
+}
+
+const listUsage = "list [-json] [-limit n] [-folder f] [-mailbox m] [-caller number] [-tags t] [-unread] [-from day] [-until day] [words...]"
+
+func runListCommand(db model.Database, mailboxes model.Mailboxes, args []string) int {
+	fs, asJSON := commandFlags("list", listUsage)
+	limit := fs.Int("limit", 20, "Maximum number of voicemails (0 for all)")
+	query := filterFlags(fs, mailboxes, "inbox")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	folder, filter, err := query(fs.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "list: %v\n", err)
+		return 2
+	}
+
+	page, err := db.GetPage(model.Query{Folder: folder, Filter: filter, Limit: *limit})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	list, rows := []jsonVoicemail{}, [][]string{}
+	for _, voicemail := range page.Voicemails {
+		v := newJSONVoicemail(voicemail, mailboxes)
+		flags := []string{}
+		if !voicemail.Read {
+			flags = append(flags, "unread")
+		}
+		if voicemail.Starred {
+			flags = append(flags, "starred")
+		}
+		list = append(list, v)
+		rows = append(rows, []string{strconv.Itoa(v.Id), v.Date.Format("2006-01-02 15:04"),
+			v.Caller, v.Called, v.Mailbox, voicemail.Duration.String(), folderName(voicemail),
+			strings.Join(flags, ","), strings.Join(v.Tags, ",")})
+	}
+	if *asJSON {
+		err = printJSON(list)
+	} else {
+		err = printTable([]string{"ID", "DATE", "CALLER", "CALLED", "MAILBOX", "DURATION", "FOLDER", "FLAGS", "TAGS"}, rows)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func runShowCommand(db model.Database, mailboxes model.Mailboxes, voicemailDir string, args []string) int {
+	fs, asJSON := commandFlags("show", "show [-json] id...")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	status := 0
+	list := []jsonVoicemail{}
+	for _, arg := range fs.Args() {
+		id, err := strconv.Atoi(arg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "show: invalid voicemail id %q\n", arg)
+			status = 1
+			continue
+		}
+		voicemail, err := db.GetVoicemail(id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "show %d: %v\n", id, err)
+			status = 1
+			continue
+		}
+		v := newJSONVoicemail(voicemail, mailboxes)
+		list = append(list, v)
+		if *asJSON {
+			continue
+		}
+
+		if len(list) > 1 {
+			fmt.Println()
+		}
+		printTable(nil, [][]string{
+			{"Id:", strconv.Itoa(v.Id)},
+			{"Date:", v.Date.Format("2006-01-02 15:04:05")},
+			{"Caller:", v.Caller},
+			{"Called:", v.Called},
+			{"Mailbox:", v.Mailbox},
+			{"Duration:", voicemail.Duration.String()},
+			{"Folder:", folderName(voicemail)},
+			{"Read:", strconv.FormatBool(v.Read)},
+			{"Starred:", strconv.FormatBool(v.Starred)},
+			{"Tags:", strings.Join(v.Tags, ", ")},
+			{"File:", path.Join(voicemailDir, v.File)},
+			{"Notes:", strings.Replace(v.Notes, "\n", " ", -1)},
+			{"Transcript:", strings.Replace(v.Transcript, "\n", " ", -1)},
+		})
+	}
+	if *asJSON {
+		if err := printJSON(list); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	return status
+}
+
+type jsonStats struct {
+	Inbox   int `json:"inbox"`
+	Archive int `json:"archive"`
+	Trash   int `json:"trash"`
+	Unread  int `json:"unread"`
+	Starred int `json:"starred"`
+	// Duration is the total length in seconds.
+	Duration   float64 `json:"duration"`
+	AudioBytes int64   `json:"audio_bytes"`
+	// Mailboxes counts the voicemails in the inbox and archive of each
+	// mailbox.
+	Mailboxes map[string]int `json:"mailboxes,omitempty"`
+}
+
+func runStatsCommand(db model.Database, mailboxes model.Mailboxes, voicemailDir string, args []string) int {
+	fs, asJSON := commandFlags("stats", "stats [-json]")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+
+	page, err := db.GetPage(model.Query{Folder: model.AllFolders})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	stats := jsonStats{}
+	if len(mailboxes) > 0 {
+		stats.Mailboxes = map[string]int{}
+		for _, mailbox := range mailboxes {
+			stats.Mailboxes[mailbox.Name] = 0
+		}
+	}
+	for _, voicemail := range page.Voicemails {
+		switch folderName(voicemail) {
+		case "inbox":
+			stats.Inbox++
+		case "archive":
+			stats.Archive++
+		case "trash":
+			stats.Trash++
+		}
+		if !voicemail.Deleted {
+			if !voicemail.Read {
+				stats.Unread++
+			}
+			if voicemail.Starred {
+				stats.Starred++
+			}
+			if mailbox, ok := mailboxes.ForNumber(voicemail.Called); ok {
+				stats.Mailboxes[mailbox.Name]++
+			}
+		}
+		stats.Duration += voicemail.Duration.Seconds()
+		if info, err := os.Stat(path.Join(voicemailDir, path.Base(voicemail.VoicemailPath))); err == nil {
+			stats.AudioBytes += info.Size()
+		}
+	}
+
+	if *asJSON {
+		err = printJSON(stats)
+	} else {
+		rows := [][]string{
+			{"Inbox:", strconv.Itoa(stats.Inbox)},
+			{"Archive:", strconv.Itoa(stats.Archive)},
+			{"Trash:", strconv.Itoa(stats.Trash)},
+			{"Unread:", strconv.Itoa(stats.Unread)},
+			{"Starred:", strconv.Itoa(stats.Starred)},
+			{"Duration:", (time.Duration(stats.Duration) * time.Second).String()},
+			{"Audio:", fmt.Sprintf("%.1f MB", float64(stats.AudioBytes)/1024/1024)},
+		}
+		for _, mailbox := range mailboxes {
+			rows = append(rows, []string{"Mailbox " + mailbox.Name + ":", strconv.Itoa(stats.Mailboxes[mailbox.Name])})
+		}
+		err = printTable(nil, rows)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// runCommand executes an administrative command instead of starting
+// the services and returns the process exit status.
+func runCommand(db model.Database, config Config, sections Sections, args []string) int {
+	switch args[0] {
+	case "list":
+		return runListCommand(db, sections.Mailboxes, args[1:])
+	case "show":
+		return runShowCommand(db, sections.Mailboxes, config.Voicemail, args[1:])
+	case "stats":
+		return runStatsCommand(db, sections.Mailboxes, config.Voicemail, args[1:])
+	case "export":
+		return runExportCommand(db, sections.Mailboxes, config.Voicemail, args[1:])
+	case "import":
+		return runImportCommand(db, args[1:])
+	case "users":
+		return runUsersCommand(db, args[1:])
+	case "tokens":
+		return runTokensCommand(db, args[1:])
+	case "deliveries":
+		return runDeliveriesCommand(db, args[1:])
+	case "empty-trash":
+		n, err := db.EmptyTrash(model.Filter{})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("%d voicemails purged\n", n)
+		return 0
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", args[0])
+		printCommands()
+		return 2
+	}
+
+	if len(args) < 2 {
+		fmt.Fprintf(os.Stderr, "%s: no voicemail id given\n", args[0])
+		return 2
+	}
+
+	status := 0
+	for _, arg := range args[1:] {
+		id, err := strconv.Atoi(arg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: invalid voicemail id %q\n", args[0], arg)
+			status = 1
+			continue
+		}
+
+		if err := cmd.action(db, id); err != nil {
+			fmt.Fprintf(os.Stderr, "%s %d: %v\n", args[0], id, err)
+			status = 1
+		}
+	}
+
+	return status
+}
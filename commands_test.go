@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"./model"
+)
+
+// runTestCommand runs a command and returns its output.
+func runTestCommand(t *testing.T, db model.Database, config Config, sections Sections, args ...string) (string, int) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	status := runCommand(db, config, sections, args)
+	os.Stdout = stdout
+	w.Close()
+	out, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out), status
+}
+
+func TestExportImport(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "voicemail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	var config Config
+	sections := Sections{Mailboxes: model.Mailboxes{{Name: "home", Numbers: []string{"111"}}}}
+	open := func(name string) model.Database {
+		config.Voicemail = path.Join(tempDir, name)
+		if err := os.Mkdir(config.Voicemail, 0700); err != nil {
+			t.Fatal(err)
+		}
+		return model.OpenDatabase(path.Join(config.Voicemail, "voicemail.sqlite"), config.Voicemail)
+	}
+
+	db := open("old")
+	defer db.Close()
+	for i, caller := range []string{"", "030123456"} {
+		if err := db.AddVoicemail(model.Voicemail{
+			Caller:   caller,
+			Called:   "111",
+			Date:     time.Date(2024, 3, 1+i, 12, 0, 0, 0, time.Local),
+			Duration: 5 * time.Second,
+		}, []byte("audio")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.StarVoicemail(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SetTags(2, []string{"arbeit"}); err != nil {
+		t.Fatal(err)
+	}
+
+	exportDir := path.Join(tempDir, "export")
+	if out, status := runTestCommand(t, db, config, sections, "export", exportDir); status != 0 ||
+		out != "2 voicemails exported to "+exportDir+"\n" {
+		t.Fatalf("Unexpected export %d: %s", status, out)
+	}
+
+	newDB := open("new")
+	defer newDB.Close()
+	for _, expected := range []string{"2 voicemails imported, 0 already stored, 0 failed\n", "0 voicemails imported, 2 already stored, 0 failed\n"} {
+		if out, status := runTestCommand(t, newDB, config, sections, "import", exportDir); status != 0 || out != expected {
+			t.Errorf("Unexpected import %d: %q", status, out)
+		}
+	}
+
+	out, status := runTestCommand(t, newDB, config, sections, "list", "-json", "-tags", "arbeit")
+	var list []jsonVoicemail
+	if err := json.Unmarshal([]byte(out), &list); status != 0 || err != nil {
+		t.Fatalf("Unexpected list %d: %s", status, out)
+	}
+	if len(list) != 1 || list[0].Caller != "030123456" || list[0].Mailbox != "home" ||
+		!list[0].Starred || list[0].Duration != 5 {
+		t.Errorf("Unexpected voicemails %+v", list)
+	}
+
+	out, _ = runTestCommand(t, newDB, config, sections, "list", "-folder", "all")
+	if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 3 ||
+		!strings.HasPrefix(lines[0], "ID") || !strings.Contains(lines[2], model.UnknownNumber) {
+		t.Errorf("Unexpected table:\n%s", out)
+	}
+
+	if _, status := runTestCommand(t, newDB, config, sections, "list", "-folder", "nope"); status != 2 {
+		t.Errorf("Unknown folder accepted: %d", status)
+	}
+}
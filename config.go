@@ -0,0 +1,388 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"./mail"
+	"./model"
+	"./notify"
+	"./utils"
+)
+
+// envPrefix starts the names of the environment variables that
+// override settings, e.g. VOICEMAIL_HTTP_PORT for -http-port.
+const envPrefix = "VOICEMAIL_"
+
+// sections are the settings that name a JSON file.  The config file may
+// contain their content inline instead.
+var sections = map[string]bool{
+	"mailboxes": true,
+	"webhooks":  true,
+	"email":     true,
+	"mqtt":      true,
+	"hooks":     true,
+	"poll":      true,
+}
+
+// Config holds the settings of the server.  Every setting is a flag,
+// which may also be given in the config file or the environment.  Flags
+// take precedence over the environment, which takes precedence over the
+// config file.
+type Config struct {
+	File              string
+	Host              string
+	User              string
+	Chroot            string
+	Database          string
+	Voicemail         string
+	HTTPPort          string
+	SMTPPort          string
+	Mailboxes         string
+	Webhooks          string
+	Email             string
+	MQTT              string
+	Hooks             string
+	Poll              string
+	PublicURL         string
+	Limit             int
+	Retention         model.RetentionPolicy
+	RetentionMaxSize  int64
+	RetentionInterval time.Duration
+	ShutdownTimeout   time.Duration
+	MinFreeSpace      int64
+	LogOutput         string
+	LogLevel          string
+	LogMaxSize        int64
+	LogMaxFiles       int
+
+	// inline are the sections given in the config file.
+	inline map[string]json.RawMessage
+}
+
+// flags registers the settings as flags.
+func (c *Config) flags(fs *flag.FlagSet) {
+	fs.StringVar(&c.File, "config", "", "JSON config file with the settings below")
+	fs.StringVar(&c.Host, "host", "localhost", "Hostname or IP to bind to")
+	fs.StringVar(&c.User, "user", "nobody", "User to drop to after binding")
+	fs.StringVar(&c.Chroot, "chroot", "", "Directory to confine the server to after binding, e.g. the voicemail directory")
+	fs.StringVar(&c.Database, "database", "./voicemail.sqlite", "Database file location")
+	fs.StringVar(&c.Voicemail, "voicemail", "./mp3/", "Voicemail storage directory")
+	fs.StringVar(&c.HTTPPort, "http-port", "8080", "Port for the HTTP service")
+	fs.StringVar(&c.SMTPPort, "smtp-port", "2500", "Port for the SMTP service")
+	fs.StringVar(&c.Mailboxes, "mailboxes", "", "JSON file with mailbox definitions")
+	fs.StringVar(&c.Webhooks, "webhooks", "", "JSON file with webhooks to call for new voicemails")
+	fs.StringVar(&c.Email, "email", "", "JSON file with the relay and recipients for forwarding voicemails by email")
+	fs.StringVar(&c.MQTT, "mqtt", "", "JSON file with the MQTT broker to publish voicemail events to")
+	fs.StringVar(&c.Hooks, "hooks", "", "JSON file with commands to run for voicemail events")
+	fs.StringVar(&c.Poll, "poll", "", "JSON file with the IMAP or POP3 mailbox to fetch voicemail emails from")
+	fs.StringVar(&c.PublicURL, "public-url", "", "Base URL of the web interface for links in notifications and feeds")
+	fs.IntVar(&c.Limit, "limit", 50, "Voicemails per page in the web interface (-1 for all)")
+	fs.DurationVar(&c.Retention.MaxAge, "retention-max-age", 0, "Purge voicemails older than this (e.g. 8760h)")
+	fs.IntVar(&c.Retention.MaxCount, "retention-max-count", 0, "Purge the oldest voicemails beyond this many")
+	fs.Int64Var(&c.RetentionMaxSize, "retention-max-size", 0, "Purge the oldest voicemails beyond this many megabytes of audio")
+	fs.BoolVar(&c.Retention.KeepStarred, "retention-keep-starred", true, "Never purge starred voicemails")
+	fs.DurationVar(&c.RetentionInterval, "retention-interval", time.Hour, "How often retention rules are enforced")
+	fs.DurationVar(&c.ShutdownTimeout, "shutdown-timeout", 30*time.Second, "How long to wait for active connections when shutting down")
+	fs.Int64Var(&c.MinFreeSpace, "min-free-space", 100, "Report not ready below this many megabytes free in the voicemail directory")
+	fs.StringVar(&c.LogOutput, "log-output", "stderr", "Where to log: stderr, syslog or a file name")
+	fs.StringVar(&c.LogLevel, "log-level", "info", "Lowest level that is logged: debug, info, warn or error")
+	fs.Int64Var(&c.LogMaxSize, "log-max-size", 10, "Rotate the log file at this many megabytes (0 to never rotate)")
+	fs.IntVar(&c.LogMaxFiles, "log-max-files", 5, "Number of rotated log files to keep")
+}
+
+// envName returns the environment variable of a flag.
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.Replace(flagName, "-", "_", -1))
+}
+
+// load applies the config file and the environment to the flags that
+// were not set on the command line and validates the result.
+func (c *Config) load(fs *flag.FlagSet, lookupEnv func(string) (string, bool)) error {
+	explicit := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	if value, ok := lookupEnv(envName("config")); ok && !explicit["config"] {
+		c.File = value
+	}
+	c.inline = map[string]json.RawMessage{}
+	if c.File != "" {
+		data, err := ioutil.ReadFile(c.File)
+		if err != nil {
+			return err
+		}
+		settings := map[string]string{}
+		if err := c.parse(fs, data, "", "", settings); err != nil {
+			return fmt.Errorf("%s: %v", c.File, err)
+		}
+
+		names := []string{}
+		for name := range settings {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if explicit[name] {
+				continue
+			}
+			if err := fs.Set(name, settings[name]); err != nil {
+				return fmt.Errorf("%s: invalid value %q for %s: %v", c.File, settings[name], name, err)
+			}
+		}
+	}
+
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		value, ok := lookupEnv(envName(f.Name))
+		if !ok || err != nil || explicit[f.Name] || f.Name == "config" {
+			return
+		}
+		if err = f.Value.Set(value); err != nil {
+			err = fmt.Errorf("invalid value %q for %s: %v", value, envName(f.Name), err)
+		}
+		delete(c.inline, f.Name)
+	})
+	if err != nil {
+		return err
+	}
+	for name := range explicit {
+		delete(c.inline, name)
+	}
+
+	return c.validate()
+}
+
+// parse collects the settings of a JSON object in the config file.
+// Nested objects are joined to flag names, e.g. {"retention":
+// {"max_age": "8760h"}} sets -retention-max-age.
+func (c *Config) parse(fs *flag.FlagSet, data []byte, prefix string, path string, settings map[string]string) error {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+
+	for key, raw := range object {
+		name := prefix + strings.Replace(key, "_", "-", -1)
+		keyPath := path + key
+		raw = bytes.TrimSpace(raw)
+
+		if sections[name] && len(raw) > 0 && raw[0] != '"' {
+			c.inline[name] = raw
+			continue
+		}
+		if len(raw) > 0 && raw[0] == '{' {
+			if err := c.parse(fs, raw, name+"-", keyPath+".", settings); err != nil {
+				return err
+			}
+			continue
+		}
+		if name == "config" || fs.Lookup(name) == nil {
+			return fmt.Errorf("unknown setting %q", keyPath)
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+		switch v := value.(type) {
+		case string:
+			settings[name] = v
+		case json.Number:
+			settings[name] = v.String()
+		case bool:
+			settings[name] = strconv.FormatBool(v)
+		default:
+			return fmt.Errorf("%s must be a string, number or boolean", keyPath)
+		}
+	}
+	return nil
+}
+
+// logConfig returns the logging settings.
+func (c Config) logConfig() utils.LogConfig {
+	level, _ := utils.ParseLevel(c.LogLevel)
+	return utils.LogConfig{
+		Output:   c.LogOutput,
+		Level:    level,
+		MaxSize:  c.LogMaxSize * 1024 * 1024,
+		MaxFiles: c.LogMaxFiles,
+	}
+}
+
+// Sections holds the parsed sections of a config.
+type Sections struct {
+	Mailboxes model.Mailboxes
+	Webhooks  []notify.Webhook
+	Email     notify.EmailConfig
+	MQTT      notify.MQTTConfig
+	Hooks     notify.HookConfig
+	Poll      mail.PollConfig
+}
+
+// loadSections parses the inline sections and the files they name.
+func (c Config) loadSections() (Sections, error) {
+	var s Sections
+	if err := c.section("mailboxes", c.Mailboxes, func(data []byte) (err error) {
+		s.Mailboxes, err = model.ParseMailboxes(data)
+		return err
+	}); err != nil {
+		return Sections{}, err
+	}
+	if err := c.section("webhooks", c.Webhooks, func(data []byte) (err error) {
+		s.Webhooks, err = notify.ParseWebhooks(data)
+		return err
+	}); err != nil {
+		return Sections{}, err
+	}
+	if err := c.section("email", c.Email, func(data []byte) (err error) {
+		s.Email, err = notify.ParseEmailConfig(data)
+		return err
+	}); err != nil {
+		return Sections{}, err
+	}
+	if err := c.section("mqtt", c.MQTT, func(data []byte) (err error) {
+		s.MQTT, err = notify.ParseMQTTConfig(data)
+		return err
+	}); err != nil {
+		return Sections{}, err
+	}
+	if err := c.section("hooks", c.Hooks, func(data []byte) (err error) {
+		s.Hooks, err = notify.ParseHookConfig(data)
+		return err
+	}); err != nil {
+		return Sections{}, err
+	}
+	if err := c.section("poll", c.Poll, func(data []byte) (err error) {
+		s.Poll, err = mail.ParsePollConfig(data)
+		return err
+	}); err != nil {
+		return Sections{}, err
+	}
+	return s, nil
+}
+
+// reloadConfig reads the config again with the original command line
+// and environment.
+func reloadConfig() (Config, Sections, error) {
+	var config Config
+	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	config.flags(fs)
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		return Config{}, Sections{}, err
+	}
+	if err := config.load(fs, os.LookupEnv); err != nil {
+		return Config{}, Sections{}, err
+	}
+	sections, err := config.loadSections()
+	return config, sections, err
+}
+
+// restartRequired returns the settings that differ from another config
+// and cannot be changed while the server is running.
+func (c Config) restartRequired(other Config) []string {
+	changed := []string{}
+	for _, setting := range []struct {
+		name     string
+		old, new string
+	}{
+		{"host", c.Host, other.Host},
+		{"user", c.User, other.User},
+		{"chroot", c.Chroot, other.Chroot},
+		{"database", c.Database, other.Database},
+		{"voicemail", c.Voicemail, other.Voicemail},
+		{"http-port", c.HTTPPort, other.HTTPPort},
+		{"smtp-port", c.SMTPPort, other.SMTPPort},
+	} {
+		if setting.old != setting.new {
+			changed = append(changed, setting.name)
+		}
+	}
+	return changed
+}
+
+// section parses the inline section of the config file with the name
+// of a flag or, if there is none, the file given by the flag.  It does
+// nothing if neither is set.
+func (c Config) section(name string, file string, parse func([]byte) error) error {
+	if raw, ok := c.inline[name]; ok {
+		if err := parse(raw); err != nil {
+			return fmt.Errorf("%s: %s: %v", c.File, name, err)
+		}
+		return nil
+	}
+	if file == "" {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if err := parse(data); err != nil {
+		return fmt.Errorf("%s: %v", file, err)
+	}
+	return nil
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n < 65536
+}
+
+// validate checks the settings that flag parsing cannot check.
+func (c Config) validate() error {
+	switch {
+	case c.Host == "":
+		return fmt.Errorf("host must not be empty")
+	case c.User == "":
+		return fmt.Errorf("user must not be empty")
+	case c.Database == "":
+		return fmt.Errorf("database must not be empty")
+	case c.Voicemail == "":
+		return fmt.Errorf("voicemail must not be empty")
+	case !validPort(c.HTTPPort):
+		return fmt.Errorf("invalid http-port %q", c.HTTPPort)
+	case !validPort(c.SMTPPort):
+		return fmt.Errorf("invalid smtp-port %q", c.SMTPPort)
+	case c.Limit == 0 || c.Limit < -1:
+		return fmt.Errorf("invalid limit %d, expected -1 or more than 0", c.Limit)
+	case c.Retention.MaxAge < 0 || c.Retention.MaxCount < 0 || c.RetentionMaxSize < 0:
+		return fmt.Errorf("retention limits must not be negative")
+	case c.RetentionInterval <= 0:
+		return fmt.Errorf("retention-interval must be positive")
+	case c.ShutdownTimeout <= 0:
+		return fmt.Errorf("shutdown-timeout must be positive")
+	case c.LogOutput == "":
+		return fmt.Errorf("log-output must not be empty")
+	case c.LogMaxSize < 0 || c.LogMaxFiles < 0:
+		return fmt.Errorf("log-max-size and log-max-files must not be negative")
+	case c.Chroot != "" && c.LogOutput != "stderr" && c.LogOutput != "syslog":
+		return fmt.Errorf("log files cannot be rotated in a chroot, log to stderr or syslog")
+	}
+
+	if _, err := utils.ParseLevel(c.LogLevel); err != nil {
+		return err
+	}
+
+	if c.PublicURL != "" {
+		u, err := url.Parse(c.PublicURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid public-url %q", c.PublicURL)
+		}
+	}
+	return nil
+}
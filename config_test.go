@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+)
+
+func loadTestConfig(t *testing.T, file string, env map[string]string, args ...string) (Config, error) {
+	var config Config
+	fs := flag.NewFlagSet("voicemail", flag.ContinueOnError)
+	config.flags(fs)
+	if err := fs.Parse(append([]string{"-config", file}, args...)); err != nil {
+		t.Fatal(err)
+	}
+	err := config.load(fs, func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	})
+	return config, err
+}
+
+func TestConfig(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "voicemail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	file := path.Join(tempDir, "config.json")
+	writeConfig := func(s string) {
+		if err := ioutil.WriteFile(file, []byte(s), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeConfig(`{
+		"host": "0.0.0.0",
+		"http_port": 80,
+		"smtp_port": "25",
+		"limit": 20,
+		"retention": {"max_age": "8760h", "keep_starred": false},
+		"webhooks": "/etc/voicemail/webhooks.json",
+		"mailboxes": [{"name": "home", "numbers": ["111"]}]
+	}`)
+
+	config, err := loadTestConfig(t, file, map[string]string{
+		"VOICEMAIL_LIMIT":     "30",
+		"VOICEMAIL_SMTP_PORT": "2525",
+	}, "-limit", "40")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Host != "0.0.0.0" || config.HTTPPort != "80" || config.SMTPPort != "2525" ||
+		config.Limit != 40 || config.Database != "./voicemail.sqlite" {
+		t.Errorf("Unexpected settings: %+v", config)
+	}
+	if config.Retention.MaxAge != 8760*time.Hour || config.Retention.KeepStarred {
+		t.Errorf("Unexpected retention policy: %+v", config.Retention)
+	}
+	if config.Webhooks != "/etc/voicemail/webhooks.json" {
+		t.Errorf("Unexpected webhooks file: %q", config.Webhooks)
+	}
+
+	var mailboxes string
+	err = config.section("mailboxes", config.Mailboxes, func(data []byte) error {
+		mailboxes = string(data)
+		return nil
+	})
+	if err != nil || !strings.Contains(mailboxes, `"home"`) {
+		t.Errorf("Unexpected mailboxes section %q: %v", mailboxes, err)
+	}
+
+	config, err = loadTestConfig(t, file, nil, "-mailboxes", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := config.inline["mailboxes"]; ok {
+		t.Errorf("Inline mailboxes used despite flag")
+	}
+
+	for _, test := range []struct {
+		config string
+		env    map[string]string
+		err    string
+	}{
+		{`{"retention": {"max_agee": "1h"}}`, nil, `unknown setting "retention.max_agee"`},
+		{`{"limit": "many"}`, nil, `invalid value "many" for limit`},
+		{`{"limit": 0}`, nil, "invalid limit 0"},
+		{`{"http_port": [80]}`, nil, "http_port must be a string"},
+		{`{}`, map[string]string{"VOICEMAIL_HTTP_PORT": "http"}, `invalid http-port "http"`},
+		{`{}`, map[string]string{"VOICEMAIL_RETENTION_MAX_AGE": "1y"}, "VOICEMAIL_RETENTION_MAX_AGE"},
+		{`{"public_url": "example.com"}`, nil, "invalid public-url"},
+		{`{"host": "localhost",}`, nil, "invalid character"},
+		{`{"chroot": "/var/db/voicemail", "log_output": "voicemail.log"}`, nil, "cannot be rotated in a chroot"},
+	} {
+		writeConfig(test.config)
+		_, err := loadTestConfig(t, file, test.env)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("Expected error %q for %s, got %v", test.err, test.config, err)
+		}
+	}
+}
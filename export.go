@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"./mail"
+	"./model"
+)
+
+// exportIndex lists the exported voicemails with their details.  The
+// MP3 files are stored next to it.
+const exportIndex = "voicemails.json"
+
+const exportUsage = "export [-folder f] [-mailbox m] [-caller number] [-tags t] [-unread] [-from day] [-until day] dir [words...]"
+
+func runExportCommand(db model.Database, mailboxes model.Mailboxes, voicemailDir string, args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s\n", exportUsage)
+		fs.PrintDefaults()
+	}
+	query := filterFlags(fs, mailboxes, "all")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	dir := fs.Arg(0)
+	folder, filter, err := query(fs.Args()[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "export: %v\n", err)
+		return 2
+	}
+
+	page, err := db.GetPage(model.Query{Folder: folder, Filter: filter})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		fmt.Fprintf(os.Stderr, "export: %v\n", err)
+		return 1
+	}
+
+	status := 0
+	list := []jsonVoicemail{}
+	for _, voicemail := range page.Voicemails {
+		v := newJSONVoicemail(voicemail, mailboxes)
+		audio, err := ioutil.ReadFile(path.Join(voicemailDir, v.File))
+		if err == nil {
+			err = ioutil.WriteFile(path.Join(dir, v.File), audio, 0640)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "export %d: %v\n", v.Id, err)
+			status = 1
+			continue
+		}
+		list = append(list, v)
+	}
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err == nil {
+		err = ioutil.WriteFile(path.Join(dir, exportIndex), append(data, '\n'), 0640)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "export: %v\n", err)
+		return 1
+	}
+	fmt.Printf("%d voicemails exported to %s\n", len(list), dir)
+	return status
+}
+
+const importUsage = "import [-dry-run] dir|file..."
+
+// importReport counts the results of an import.
+type importReport struct {
+	dryRun     bool
+	imported   int
+	duplicates int
+	failed     int
+	// seen holds the voicemails found in a dry run, which are not
+	// stored and thus not detected as duplicates by the database.
+	seen map[string]bool
+}
+
+// add counts a voicemail that was imported or failed with err.
+func (r *importReport) add(name string, voicemail model.Voicemail, err error) {
+	key := voicemail.Caller + "\xff" + voicemail.Called + "\xff" + voicemail.Date.String()
+	switch {
+	case err == mail.ErrDuplicate || (err == nil && r.seen[key]):
+		r.duplicates++
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "import %s: %v\n", name, err)
+		r.failed++
+	default:
+		r.seen[key] = true
+		r.imported++
+	}
+}
+
+// importExport adds the voicemails written by export to dir.
+func importExport(db model.Database, dir string, report *importReport) error {
+	var list []jsonVoicemail
+	data, err := ioutil.ReadFile(path.Join(dir, exportIndex))
+	if err == nil {
+		err = json.Unmarshal(data, &list)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, v := range list {
+		voicemail := v.voicemail()
+		exists, err := db.HasVoicemail(voicemail)
+		if err == nil && exists {
+			err = mail.ErrDuplicate
+		}
+		var audio []byte
+		if err == nil {
+			audio, err = ioutil.ReadFile(path.Join(dir, path.Base(v.File)))
+		}
+		if err == nil && !report.dryRun {
+			_, err = db.ImportVoicemail(voicemail, audio)
+		}
+		report.add(v.File, voicemail, err)
+	}
+	return nil
+}
+
+func runImportCommand(db model.Database, args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s\n", importUsage)
+		fs.PrintDefaults()
+	}
+	dryRun := fs.Bool("dry-run", false, "Only report what would be imported")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	report := &importReport{dryRun: *dryRun, seen: map[string]bool{}}
+	status := 0
+	for _, source := range fs.Args() {
+		var err error
+		if _, statErr := os.Stat(path.Join(source, exportIndex)); statErr == nil {
+			err = importExport(db, source, report)
+		} else {
+			err = mail.ReadMessages(source, func(name string, msg string) error {
+				voicemail, err := mail.ImportMessage(db, msg, *dryRun)
+				report.add(name, voicemail, err)
+				return nil
+			})
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "import %s: %v\n", source, err)
+			status = 1
+		}
+	}
+
+	if report.dryRun {
+		fmt.Printf("%d voicemails would be imported, %d already stored, %d failed\n",
+			report.imported, report.duplicates, report.failed)
+	} else {
+		fmt.Printf("%d voicemails imported, %d already stored, %d failed\n",
+			report.imported, report.duplicates, report.failed)
+	}
+	if report.failed > 0 {
+		status = 1
+	}
+	return status
+}
@@ -0,0 +1,147 @@
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// listeners holds the addresses that Serve accepts connections on.
+var listeners = struct {
+	sync.Mutex
+	addrs map[net.Addr]int
+}{addrs: map[net.Addr]int{}}
+
+// listenerCheckTimeout limits how long CheckListener waits for the
+// greeting.  Serve greets after storing the voicemail it is receiving.
+var listenerCheckTimeout = 3 * time.Second
+
+// CheckListener connects to the addresses of Serve and reports an error
+// unless each answers with the SMTP greeting.
+func CheckListener() error {
+	listeners.Lock()
+	addrs := []net.Addr{}
+	for addr := range listeners.addrs {
+		addrs = append(addrs, addr)
+	}
+	listeners.Unlock()
+
+	if len(addrs) == 0 {
+		return errors.New("SMTP server not running")
+	}
+	for _, addr := range addrs {
+		if err := checkGreeting(addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkGreeting reads the greeting of the server at addr and ends the
+// session.
+func checkGreeting(addr net.Addr) error {
+	address := addr.String()
+	if tcp, ok := addr.(*net.TCPAddr); ok && tcp.IP.IsUnspecified() {
+		ip := net.IPv6loopback
+		if tcp.IP.To4() != nil {
+			ip = net.IPv4(127, 0, 0, 1)
+		}
+		address = net.JoinHostPort(ip.String(), fmt.Sprint(tcp.Port))
+	}
+
+	conn, err := net.DialTimeout(addr.Network(), address, listenerCheckTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(listenerCheckTimeout))
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("no greeting: %v", err)
+	}
+	if !strings.HasPrefix(line, "220 ") {
+		return fmt.Errorf("unexpected greeting %q", strings.TrimSpace(line))
+	}
+	_, err = fmt.Fprint(conn, "QUIT\r\n")
+	return err
+}
+
+// waveFormat is the fmt chunk of a WAV file.
+type waveFormat struct {
+	Size          uint32
+	Format        uint16
+	Channels      uint16
+	SampleRate    uint32
+	ByteRate      uint32
+	BlockAlign    uint16
+	BitsPerSample uint16
+}
+
+// testAudio returns a quarter of a second of silence in the A-law WAV
+// format the FRITZ!Box uses.
+func testAudio() []byte {
+	samples := bytes.Repeat([]byte{0xd5}, 2000)
+	var wav bytes.Buffer
+	wav.WriteString("RIFF")
+	binary.Write(&wav, binary.LittleEndian, uint32(36+len(samples)))
+	wav.WriteString("WAVEfmt ")
+	binary.Write(&wav, binary.LittleEndian, waveFormat{
+		Size: 16, Format: 6, Channels: 1, SampleRate: 8000,
+		ByteRate: 8000, BlockAlign: 1, BitsPerSample: 8,
+	})
+	wav.WriteString("data")
+	binary.Write(&wav, binary.LittleEndian, uint32(len(samples)))
+	wav.Write(samples)
+	return wav.Bytes()
+}
+
+// cachedCheck runs a check at most once per interval and reports the
+// last result in between.
+type cachedCheck struct {
+	check    func() error
+	interval time.Duration
+
+	lock    sync.Mutex
+	checked time.Time
+	err     error
+}
+
+func (c *cachedCheck) run() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.checked.IsZero() || time.Since(c.checked) >= c.interval {
+		c.err = c.check()
+		c.checked = time.Now()
+	}
+	return c.err
+}
+
+// transcoderCheck starts at most one conversion a minute, however
+// often /readyz is requested.
+var transcoderCheck = &cachedCheck{check: checkTranscoder, interval: time.Minute}
+
+// CheckTranscoder reports whether mplayer and lame converted a short
+// recording when last checked.
+func CheckTranscoder() error {
+	return transcoderCheck.run()
+}
+
+// checkTranscoder converts a short recording to check that mplayer and
+// lame work.
+func checkTranscoder() error {
+	mp3, err := transcode(bytes.NewReader(testAudio()))
+	if err != nil {
+		return err
+	}
+	if len(mp3) == 0 {
+		return errors.New("transcoder returned no audio")
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// imapSearch selects the unseen messages sent by the FRITZ!Box.
+const imapSearch = `UID SEARCH UNSEEN HEADER Content-Type "==AVM_Fritz_Box=="`
+
+// imapClient is a minimal IMAP4rev1 client.
+type imapClient struct {
+	*mailConn
+	tag          int
+	capabilities map[string]bool
+}
+
+// imapResponse is a response line with the literals it contains.
+type imapResponse struct {
+	line     string
+	literals []string
+}
+
+// imapQuote returns s as a quoted string.
+func imapQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// literalSize returns n for a line that ends with {n}, which announces
+// a literal of n bytes.
+func literalSize(line string) (int, bool) {
+	i := strings.LastIndex(line, "{")
+	if i < 0 || !strings.HasSuffix(line, "}") {
+		return 0, false
+	}
+	n, err := strconv.Atoi(line[i+1 : len(line)-1])
+	return n, err == nil && n >= 0
+}
+
+func (c *imapClient) readResponse() (imapResponse, error) {
+	var r imapResponse
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return imapResponse{}, err
+		}
+		r.line += line
+		n, ok := literalSize(line)
+		if !ok {
+			return r, nil
+		}
+		literal := make([]byte, n)
+		if _, err := io.ReadFull(c.r, literal); err != nil {
+			return imapResponse{}, err
+		}
+		r.literals = append(r.literals, string(literal))
+	}
+}
+
+// command sends a command and returns the untagged responses if it
+// succeeds.
+func (c *imapClient) command(format string, args ...interface{}) ([]imapResponse, error) {
+	c.tag++
+	tag := "a" + strconv.Itoa(c.tag)
+	command := fmt.Sprintf(format, args...)
+	if err := c.writeLine(tag + " " + command); err != nil {
+		return nil, err
+	}
+	return c.result(tag, strings.Fields(command)[0])
+}
+
+// result reads responses up to the tagged one.
+func (c *imapClient) result(tag string, command string) ([]imapResponse, error) {
+	responses := []imapResponse{}
+	for {
+		r, err := c.readResponse()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case strings.HasPrefix(r.line, tag+" "):
+			status := strings.TrimPrefix(r.line, tag+" ")
+			if !strings.HasPrefix(strings.ToUpper(status), "OK") {
+				return nil, fmt.Errorf("%s failed: %s", command, status)
+			}
+			return responses, nil
+		case strings.HasPrefix(r.line, "* "):
+			responses = append(responses, r)
+		}
+	}
+}
+
+// capability reads the capabilities of the server.
+func (c *imapClient) capability() error {
+	responses, err := c.command("CAPABILITY")
+	if err != nil {
+		return err
+	}
+	c.capabilities = map[string]bool{}
+	for _, r := range responses {
+		fields := strings.Fields(strings.ToUpper(r.line))
+		if len(fields) > 1 && fields[1] == "CAPABILITY" {
+			for _, capability := range fields[2:] {
+				c.capabilities[capability] = true
+			}
+		}
+	}
+	return nil
+}
+
+// idle waits until the server reports a new message or the timeout
+// expires.
+func (c *imapClient) idle(timeout time.Duration) error {
+	c.tag++
+	tag := "a" + strconv.Itoa(c.tag)
+	if err := c.writeLine(tag + " IDLE"); err != nil {
+		return err
+	}
+	if r, err := c.readResponse(); err != nil {
+		return err
+	} else if !strings.HasPrefix(r.line, "+") {
+		return fmt.Errorf("IDLE failed: %s", r.line)
+	}
+
+	c.SetReadDeadline(time.Now().Add(timeout))
+	for {
+		r, err := c.readResponse()
+		if err, ok := err.(net.Error); ok && err.Timeout() {
+			break
+		} else if err != nil {
+			return err
+		}
+		if fields := strings.Fields(strings.ToUpper(r.line)); len(fields) == 3 && fields[2] == "EXISTS" {
+			break
+		}
+	}
+
+	if err := c.writeLine("DONE"); err != nil {
+		return err
+	}
+	_, err := c.result(tag, "IDLE")
+	return err
+}
+
+// dialIMAP connects, logs in and selects the mailbox.
+func (p *poller) dialIMAP() (*imapClient, error) {
+	conn, err := p.dial()
+	if err != nil {
+		return nil, err
+	}
+	c := &imapClient{mailConn: conn}
+
+	if r, err := c.readResponse(); err != nil {
+		conn.Close()
+		return nil, err
+	} else if !strings.HasPrefix(strings.ToUpper(r.line), "* OK") {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected greeting %q", r.line)
+	}
+	err = c.capability()
+	if err == nil && !p.config.TLS {
+		switch {
+		case c.capabilities["STARTTLS"]:
+			if _, err = c.command("STARTTLS"); err == nil {
+				if err = c.startTLS(p.config.Server); err == nil {
+					err = c.capability()
+				}
+			}
+		case !p.config.Insecure:
+			err = errCleartext
+		}
+	}
+	if err == nil {
+		_, err = c.command("LOGIN %s %s", imapQuote(p.config.Username), imapQuote(p.config.Password))
+	}
+	if err == nil {
+		err = c.capability()
+	}
+	if err == nil {
+		_, err = c.command("SELECT %s", imapQuote(p.config.Mailbox))
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// pollIMAP processes new messages.  If the server supports IDLE, it
+// keeps waiting for new messages until the connection fails.
+func (p *poller) pollIMAP() error {
+	c, err := p.dialIMAP()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	p.log.Debug("Connected to mail server", "protocol", "imap", "mailbox", p.config.Mailbox)
+
+	for {
+		responses, err := c.command(imapSearch)
+		if err != nil {
+			return err
+		}
+		for _, r := range responses {
+			fields := strings.Fields(r.line)
+			if len(fields) < 2 || strings.ToUpper(fields[1]) != "SEARCH" {
+				continue
+			}
+			for _, uid := range fields[2:] {
+				if p.stopped() {
+					return errStopped
+				}
+				if err := p.fetchIMAP(c, uid); err != nil {
+					return err
+				}
+			}
+		}
+		polls.Inc("ok")
+
+		if !c.capabilities["IDLE"] {
+			_, err := c.command("LOGOUT")
+			return err
+		}
+		timeout := p.config.Interval
+		if timeout > imapIdleTimeout {
+			timeout = imapIdleTimeout
+		}
+		if err := c.idle(timeout); err != nil {
+			return err
+		}
+	}
+}
+
+// fetchIMAP processes a message.  It is moved or marked as seen if it
+// was stored, and flagged if it could not be processed.  It is left
+// unseen if storing it failed, so that it is fetched again.
+func (p *poller) fetchIMAP(c *imapClient, uid string) error {
+	responses, err := c.command("UID FETCH %s BODY.PEEK[]", uid)
+	if err != nil {
+		return err
+	}
+	msg := ""
+	for _, r := range responses {
+		if strings.Contains(strings.ToUpper(r.line), " FETCH ") && len(r.literals) > 0 {
+			msg = r.literals[0]
+		}
+	}
+	if msg == "" || !isFritzBoxMessage(msg) {
+		return nil
+	}
+
+	log := p.log.With("uid", uid, "message_id", messageId(msg))
+	if err := p.deliver(log, msg); errors.Is(err, errUnparsable) {
+		_, err = c.command(`UID STORE %s +FLAGS.SILENT (\Seen \Flagged)`, uid)
+		return err
+	} else if err != nil {
+		return fmt.Errorf("unable to store message %s: %v", uid, err)
+	}
+
+	switch {
+	case p.config.MoveTo == "":
+		_, err = c.command(`UID STORE %s +FLAGS.SILENT (\Seen)`, uid)
+	case c.capabilities["MOVE"]:
+		_, err = c.command("UID MOVE %s %s", uid, imapQuote(p.config.MoveTo))
+	default:
+		if _, err = c.command("UID COPY %s %s", uid, imapQuote(p.config.MoveTo)); err != nil {
+			break
+		}
+		if _, err = c.command(`UID STORE %s +FLAGS.SILENT (\Seen \Deleted)`, uid); err != nil {
+			break
+		}
+		if c.capabilities["UIDPLUS"] {
+			_, err = c.command("UID EXPUNGE %s", uid)
+		} else {
+			_, err = c.command("EXPUNGE")
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("unable to mark message %s: %v", uid, err)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package mail
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"../model"
+)
+
+// ErrDuplicate is returned by ImportMessage for voicemails that are
+// stored already.
+var ErrDuplicate = errors.New("voicemail already stored")
+
+// ImportMessage adds the voicemail in a raw message like one received
+// by SMTP.  With dryRun, the message is only checked and its audio is
+// not converted.
+func ImportMessage(db model.Database, msg string, dryRun bool) (model.Voicemail, error) {
+	voicemail, err := extractCall(msg)
+	if err != nil {
+		return model.Voicemail{}, err
+	}
+	if exists, err := db.HasVoicemail(voicemail); err != nil {
+		return voicemail, err
+	} else if exists {
+		return voicemail, ErrDuplicate
+	}
+
+	if dryRun {
+		_, err := extractAudio(msg)
+		return voicemail, err
+	}
+	voicemailAudio, err := extractVoicemailAudio(msg)
+	if err != nil {
+		return voicemail, err
+	}
+	return voicemail, db.AddVoicemail(voicemail, voicemailAudio)
+}
+
+// ReadMessages calls f for every message in a Maildir, a directory of
+// .eml files, an mbox file or a single message file.  name identifies
+// the message in error messages.  It stops at the first error returned
+// by f.
+func ReadMessages(source string, f func(name string, msg string) error) error {
+	info, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		dirs := []string{source}
+		if isMaildir(source) {
+			dirs = []string{path.Join(source, "new"), path.Join(source, "cur")}
+		}
+		for _, dir := range dirs {
+			files, err := ioutil.ReadDir(dir)
+			if err != nil {
+				return err
+			}
+			sort.Slice(files, func(i, j int) bool { return files[i].Name() < files[j].Name() })
+			for _, file := range files {
+				if file.IsDir() || strings.HasPrefix(file.Name(), ".") ||
+					(dir == source && !strings.HasSuffix(file.Name(), ".eml")) {
+					continue
+				}
+				name := path.Join(dir, file.Name())
+				data, err := ioutil.ReadFile(name)
+				if err != nil {
+					return err
+				}
+				if err := f(name, string(data)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	file, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return readMbox(source, file, f)
+}
+
+// isMaildir reports whether dir has the subdirectories of a Maildir.
+func isMaildir(dir string) bool {
+	for _, sub := range []string{"new", "cur"} {
+		if info, err := os.Stat(path.Join(dir, sub)); err != nil || !info.IsDir() {
+			return false
+		}
+	}
+	return true
+}
+
+// readMbox splits an mbox file at the "From " lines that start every
+// message.  Files without them are read as a single message.  Quoted
+// ">From " lines are unquoted.
+func readMbox(name string, file io.Reader, f func(name string, msg string) error) error {
+	in := bufio.NewReader(file)
+	var msg strings.Builder
+	n := 0
+	isMbox := false
+
+	flush := func() error {
+		if msg.Len() == 0 {
+			return nil
+		}
+		n++
+		msgName := name
+		if isMbox {
+			msgName += ":" + strconv.Itoa(n)
+		}
+		err := f(msgName, msg.String())
+		msg.Reset()
+		return err
+	}
+
+	first := true
+	for {
+		line, err := in.ReadString('\n')
+		if line != "" {
+			switch {
+			case strings.HasPrefix(line, "From ") && (first || isMbox):
+				isMbox = true
+				if err := flush(); err != nil {
+					return err
+				}
+			case isMbox && strings.HasPrefix(strings.TrimLeft(line, ">"), "From ") && line[0] == '>':
+				msg.WriteString(line[1:])
+			default:
+				msg.WriteString(line)
+			}
+			first = false
+		}
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+	}
+	return flush()
+}
@@ -9,16 +9,46 @@ import (
 	"errors"
 )
 
-func ParseHtml(r io.Reader) (model.Voicemail, error) {
-	const (
-		Caller int = iota
-		Called
-		Duration
-		Time
-		Date
-		None
-	)
+// field is a value in the HTML part of a message.
+type field int
+
+const (
+	callerField field = iota
+	calledField
+	durationField
+	timeField
+	dateField
+	noField
+)
+
+// labels maps the labels preceding the values in the HTML part to
+// their data type.
+var labels = map[string]field{
+	"anruf von:":         callerField,
+	"für die rufnummer:": calledField,
+	"uhrzeit:":           timeField,
+	"datum:":             dateField,
+	"aufnahmelänge:":     durationField,
+}
+
+// tableLabels returns the fields of the cells of a table row if
+// all of them are labels.
+func tableLabels(row []string) []field {
+	if len(row) == 0 {
+		return nil
+	}
+	types := []field{}
+	for _, cell := range row {
+		f, ok := labels[strings.ToLower(cell)+":"]
+		if !ok {
+			return nil
+		}
+		types = append(types, f)
+	}
+	return types
+}
 
+func ParseHtml(r io.Reader) (model.Voicemail, error) {
 	var caller string = ""
 	var called string = ""
 	var durationStr string = ""
@@ -26,7 +56,28 @@ func ParseHtml(r io.Reader) (model.Voicemail, error) {
 	var dateStr string = ""
 	var err error
 
-	var nextTokenDataType = None
+	set := func(f field, value string) {
+		switch f {
+		case callerField:
+			caller = value
+		case calledField:
+			called = value
+		case durationField:
+			durationStr = value
+		case timeField:
+			timeStr = strings.TrimSuffix(value, " Uhr")
+		case dateField:
+			dateStr = value
+		}
+	}
+
+	// Older FRITZ!Box versions send a table with the labels in the
+	// header row and the values in a later row.  columns holds the
+	// fields of the header row until the values are found.
+	var columns []field
+	var row []string
+
+	var nextTokenDataType = noField
 	d := html.NewTokenizer(r)
 	for {
 		// token type
@@ -36,41 +87,42 @@ func ParseHtml(r io.Reader) (model.Voicemail, error) {
 		}
 		token := d.Token()
 		switch tokenType {
+		case html.StartTagToken:
+			switch token.Data {
+			case "tr":
+				// Rows of tables in cells start over.
+				row = nil
+			case "td", "th":
+				row = append(row, "")
+			}
+		case html.EndTagToken:
+			if token.Data != "tr" {
+				continue
+			}
+			if types := tableLabels(row); types != nil {
+				columns = types
+			} else if len(columns) > 0 && len(row) == len(columns) &&
+				row[0] != "" && !strings.HasPrefix(row[0], "(") {
+				for i, f := range columns {
+					set(f, row[i])
+				}
+				columns = nil
+			}
+			row = nil
 		case html.TextToken: // text between start and end tag
 			tokens := strings.TrimSpace(token.String())
 			if len(tokens) == 0 {
 				continue
 			}
+			if len(row) > 0 && row[len(row)-1] == "" {
+				row[len(row)-1] = tokens
+			}
 
-			switch nextTokenDataType {
-			case Caller:
-				nextTokenDataType = None
-				caller = tokens
-			case Called:
-				nextTokenDataType = None
-				called = tokens
-			case Duration:
-				nextTokenDataType = None
-				durationStr = tokens
-			case Time:
-				nextTokenDataType = None
-				timeStr = strings.TrimSuffix(tokens, " Uhr")
-			case Date:
-				nextTokenDataType = None
-				dateStr = tokens
-			case None:
-				switch strings.ToLower(tokens) {
-				case "anruf von:":
-					nextTokenDataType = Caller
-				case "für die rufnummer:":
-					nextTokenDataType = Called
-				case "uhrzeit:":
-					nextTokenDataType = Time
-				case "datum:":
-					nextTokenDataType = Date
-				case "aufnahmelänge:":
-					nextTokenDataType = Duration
-				}
+			if nextTokenDataType != noField {
+				set(nextTokenDataType, tokens)
+				nextTokenDataType = noField
+			} else if f, ok := labels[strings.ToLower(tokens)]; ok {
+				nextTokenDataType = f
 			}
 		}
 	}
@@ -82,7 +134,10 @@ func ParseHtml(r io.Reader) (model.Voicemail, error) {
 	var date time.Time
 	date, err = time.ParseInLocation("2.01.2006 15:04", dateStr+" "+timeStr, time.Local)
 	if err != nil {
-		return model.Voicemail{}, err
+		// Older versions use two-digit years.
+		if date, err = time.ParseInLocation("2.01.06 15:04", dateStr+" "+timeStr, time.Local); err != nil {
+			return model.Voicemail{}, err
+		}
 	}
 
 	if durationStr == "" {
@@ -0,0 +1,310 @@
+package mail
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+
+	"../metrics"
+	"../model"
+	. "../utils"
+)
+
+// PollConfig describes a mailbox that the FRITZ!Box sends its emails
+// to.  The mailbox is polled by IMAP or POP3 instead of receiving the
+// emails by SMTP.
+type PollConfig struct {
+	// Protocol is "imap" or "pop3".
+	Protocol string `json:"protocol"`
+	// Server is the host:port of the mail server.  Without TLS, the
+	// connection is upgraded with STARTTLS (IMAP) or STLS (POP3) before
+	// logging in.
+	Server string `json:"server"`
+	TLS    bool   `json:"tls"`
+	// Insecure allows logging in without encryption if the server does
+	// not offer STARTTLS or STLS.
+	Insecure bool   `json:"insecure"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// Mailbox is the IMAP folder that is polled, INBOX by default.
+	Mailbox string `json:"mailbox"`
+	// MoveTo is the IMAP folder that processed messages are moved to.
+	// They are only marked as seen if it is empty.
+	MoveTo string `json:"move_to"`
+	// Interval between polls.  IMAP servers that support IDLE report
+	// new messages right away.
+	Interval time.Duration `json:"-"`
+}
+
+// UnmarshalJSON reads the interval as a duration string, e.g. "5m".
+func (c *PollConfig) UnmarshalJSON(data []byte) error {
+	type pollConfig PollConfig
+	var v struct {
+		pollConfig
+		Interval string `json:"interval"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*c = PollConfig(v.pollConfig)
+	if v.Interval != "" {
+		var err error
+		if c.Interval, err = time.ParseDuration(v.Interval); err != nil {
+			return fmt.Errorf("interval: %v", err)
+		}
+	}
+	return nil
+}
+
+// ParsePollConfig reads the mailbox to poll from JSON.
+func ParsePollConfig(data []byte) (PollConfig, error) {
+	var config PollConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return PollConfig{}, err
+	}
+
+	if config.Protocol != "imap" && config.Protocol != "pop3" {
+		return PollConfig{}, fmt.Errorf("unknown protocol %q, expected imap or pop3", config.Protocol)
+	}
+	if _, _, err := net.SplitHostPort(config.Server); err != nil {
+		return PollConfig{}, fmt.Errorf("invalid server %q, expected host:port", config.Server)
+	}
+	if config.Username == "" {
+		return PollConfig{}, errors.New("username must not be empty")
+	}
+	if config.Protocol == "pop3" && (config.Mailbox != "" || config.MoveTo != "") {
+		return PollConfig{}, errors.New("mailbox and move_to are only supported by imap")
+	}
+	for _, value := range []string{config.Username, config.Password, config.Mailbox, config.MoveTo} {
+		if strings.ContainsAny(value, "\r\n") {
+			return PollConfig{}, errors.New("line breaks are not allowed in the settings")
+		}
+	}
+	if config.Interval < 0 {
+		return PollConfig{}, errors.New("interval must not be negative")
+	}
+
+	return config, nil
+}
+
+// Polling timing.  The retry delay doubles after every failed poll up
+// to the interval.
+var (
+	pollInterval = time.Minute
+	pollTimeout  = time.Minute
+	pollRetry    = 10 * time.Second
+	// imapIdleTimeout ends IDLE before servers drop idle connections.
+	imapIdleTimeout = 25 * time.Minute
+)
+
+var polls = metrics.NewCounter("voicemail_polls_total",
+	"Mailbox polls by result: ok or failed.", "result")
+
+var (
+	// errStopped is returned when polling is stopped during a poll.
+	errStopped = errors.New("polling stopped")
+	// errUnparsable is returned for messages that cannot be processed
+	// and are not fetched again.
+	errUnparsable = errors.New("message could not be processed")
+	// errCleartext is returned if the password would be sent without
+	// encryption.
+	errCleartext = errors.New("server does not offer STARTTLS or STLS, set tls or insecure")
+)
+
+// poller fetches the messages of a mailbox.
+type poller struct {
+	db     model.Database
+	config PollConfig
+	log    *Log
+	stop   chan struct{}
+	done   chan struct{}
+
+	// lock protects conn, the connection to the server, which is
+	// closed to stop a poll.
+	lock sync.Mutex
+	conn net.Conn
+
+	// skipped holds the POP3 UIDLs of messages that are left on the
+	// server.
+	skipped map[string]bool
+}
+
+// StartPoll fetches the emails of the FRITZ!Box from a mailbox and
+// stores their voicemails like those received by SMTP.  Messages are
+// marked as seen or moved (IMAP) or deleted (POP3) afterwards.  The
+// returned function stops polling.
+func StartPoll(db model.Database, config PollConfig) func() {
+	if config.Server == "" {
+		return func() {}
+	}
+	if config.Mailbox == "" {
+		config.Mailbox = "INBOX"
+	}
+	if config.Interval == 0 {
+		config.Interval = pollInterval
+	}
+
+	p := &poller{
+		db:      db,
+		config:  config,
+		log:     logger.With("server", config.Server),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+		skipped: map[string]bool{},
+	}
+	go p.run()
+	return func() {
+		close(p.stop)
+		p.lock.Lock()
+		if p.conn != nil {
+			p.conn.Close()
+		}
+		p.lock.Unlock()
+		<-p.done
+	}
+}
+
+func (p *poller) run() {
+	defer close(p.done)
+	retryDelay := pollRetry
+	for {
+		var err error
+		if p.config.Protocol == "imap" {
+			err = p.pollIMAP()
+		} else {
+			err = p.pollPOP3()
+		}
+		if p.stopped() {
+			return
+		}
+
+		wait := p.config.Interval
+		if err != nil {
+			p.log.Warn("Unable to poll mailbox", "protocol", p.config.Protocol, "error", err)
+			polls.Inc("failed")
+			if wait > retryDelay {
+				wait = retryDelay
+				retryDelay *= 2
+			}
+		} else {
+			retryDelay = pollRetry
+		}
+		if !p.sleep(wait) {
+			return
+		}
+	}
+}
+
+func (p *poller) stopped() bool {
+	select {
+	case <-p.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// sleep waits for d and returns false if polling is stopped before.
+func (p *poller) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-p.stop:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// deliver stores the voicemail of a fetched message unless it is stored
+// already.  Errors wrap errUnparsable if the message itself is at fault;
+// otherwise storing it may succeed later.
+func (p *poller) deliver(log *Log, msg string) error {
+	if voicemail, err := extractCall(msg); err == nil {
+		exists, err := p.db.HasVoicemail(voicemail)
+		if err != nil {
+			return err
+		}
+		if exists {
+			log.Info("Voicemail already stored", "caller", voicemail.Caller, "date", voicemail.Date.Format(time.RFC3339))
+			return nil
+		}
+	}
+
+	voicemail, voicemailAudio, err := parseMessage(p.db, log, msg)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUnparsable, err)
+	}
+	return storeVoicemail(p.db, log, voicemail, voicemailAudio)
+}
+
+// isFritzBoxMessage reports whether a message was sent by the FRITZ!Box.
+// Other messages in the mailbox are left alone.
+func isFritzBoxMessage(msg string) bool {
+	m, err := mail.ReadMessage(strings.NewReader(msg))
+	return err == nil && strings.Contains(m.Header.Get("Content-Type"), "==AVM_Fritz_Box==")
+}
+
+// mailConn is a line based connection to an IMAP or POP3 server.
+type mailConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (p *poller) dial() (*mailConn, error) {
+	dialer := &net.Dialer{Timeout: pollTimeout}
+	var conn net.Conn
+	var err error
+	if p.config.TLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", p.config.Server, nil)
+	} else {
+		conn, err = dialer.Dial("tcp", p.config.Server)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.stopped() {
+		conn.Close()
+		return nil, errStopped
+	}
+	p.conn = conn
+	conn.SetDeadline(time.Now().Add(pollTimeout))
+	return &mailConn{conn, bufio.NewReader(conn)}, nil
+}
+
+// startTLS continues the connection with TLS after the server agreed
+// to STARTTLS or STLS.
+func (c *mailConn) startTLS(server string) error {
+	host, _, _ := net.SplitHostPort(server)
+	conn := tls.Client(c.Conn, &tls.Config{ServerName: host})
+	if err := conn.Handshake(); err != nil {
+		return err
+	}
+	c.Conn, c.r = conn, bufio.NewReader(conn)
+	return nil
+}
+
+func (c *mailConn) readLine() (string, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (c *mailConn) writeLine(line string) error {
+	c.SetDeadline(time.Now().Add(pollTimeout))
+	_, err := c.Write([]byte(line + "\r\n"))
+	return err
+}
@@ -0,0 +1,453 @@
+package mail
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "../model"
+)
+
+// fakeMessage is a message stored by fakeMailServer.
+type fakeMessage struct {
+	uid     int
+	body    string
+	mailbox string
+	flags   map[string]bool
+}
+
+// fakeMailServer is an in-process IMAP and POP3 server with a single
+// account.
+type fakeMailServer struct {
+	sync.Mutex
+	t            *testing.T
+	capabilities string
+	messages     []*fakeMessage
+	nextUid      int
+	// fetched counts the messages downloaded by the client.
+	fetched int
+	// logins counts the passwords sent by the client.
+	logins int
+	// idle is set while a client waits with IDLE.
+	idle net.Conn
+}
+
+func (s *fakeMailServer) add(mailbox string, body string) {
+	s.Lock()
+	defer s.Unlock()
+	s.nextUid++
+	s.messages = append(s.messages, &fakeMessage{s.nextUid, body, mailbox, map[string]bool{}})
+	if s.idle != nil {
+		fmt.Fprintf(s.idle, "* %d EXISTS\r\n", len(s.messages))
+	}
+}
+
+func (s *fakeMailServer) find(uid string) *fakeMessage {
+	for _, m := range s.messages {
+		if strconv.Itoa(m.uid) == uid && m.mailbox == "INBOX" {
+			return m
+		}
+	}
+	return nil
+}
+
+// state describes the messages as "mailbox:flags" sorted by uid.
+func (s *fakeMailServer) state() string {
+	s.Lock()
+	defer s.Unlock()
+	states := []string{}
+	for _, m := range s.messages {
+		flags := []string{}
+		for _, flag := range []string{`\Seen`, `\Flagged`, `\Deleted`} {
+			if m.flags[flag] {
+				flags = append(flags, flag)
+			}
+		}
+		states = append(states, m.mailbox+":"+strings.Join(flags, " "))
+	}
+	return strings.Join(states, ",")
+}
+
+func (s *fakeMailServer) expunge(uid string) {
+	kept := []*fakeMessage{}
+	for _, m := range s.messages {
+		if !m.flags[`\Deleted`] || (uid != "" && strconv.Itoa(m.uid) != uid) {
+			kept = append(kept, m)
+		}
+	}
+	s.messages = kept
+}
+
+func (s *fakeMailServer) listen(serve func(conn net.Conn)) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				serve(conn)
+			}()
+		}
+	}()
+	return l
+}
+
+func (s *fakeMailServer) serveIMAP(conn net.Conn) {
+	fmt.Fprint(conn, "* OK fake IMAP server ready\r\n")
+	in := bufio.NewScanner(conn)
+	for in.Scan() {
+		args := strings.SplitN(in.Text(), " ", 3)
+		for len(args) < 3 {
+			args = append(args, "")
+		}
+		tag, command := args[0], strings.ToUpper(args[1])
+		if command == "UID" {
+			sub := strings.SplitN(args[2], " ", 2)
+			command, args[2] = "UID "+strings.ToUpper(sub[0]), sub[len(sub)-1]
+		}
+		uid := strings.SplitN(args[2], " ", 2)[0]
+
+		s.Lock()
+		result := "OK done"
+		switch command {
+		case "CAPABILITY":
+			fmt.Fprintf(conn, "* CAPABILITY %s\r\n", s.capabilities)
+		case "LOGIN":
+			s.logins++
+			if args[2] != `"voicemail" "s3\"cret"` {
+				result = "NO invalid credentials"
+			}
+		case "SELECT":
+			fmt.Fprintf(conn, "* %d EXISTS\r\n", len(s.messages))
+		case "UID SEARCH":
+			if args[2] != `UNSEEN HEADER Content-Type "==AVM_Fritz_Box=="` {
+				s.t.Errorf("Unexpected search %q", args[2])
+			}
+			uids := []string{}
+			for _, m := range s.messages {
+				if m.mailbox == "INBOX" && !m.flags[`\Seen`] && strings.Contains(m.body, "AVM_Fritz_Box") {
+					uids = append(uids, strconv.Itoa(m.uid))
+				}
+			}
+			fmt.Fprintf(conn, "* SEARCH %s\r\n", strings.Join(uids, " "))
+		case "UID FETCH":
+			if m := s.find(uid); m != nil {
+				s.fetched++
+				fmt.Fprintf(conn, "* 1 FETCH (UID %d BODY[] {%d}\r\n%s)\r\n", m.uid, len(m.body), m.body)
+			}
+		case "UID STORE":
+			if m := s.find(uid); m != nil {
+				flags := args[2][strings.Index(args[2], "(")+1 : len(args[2])-1]
+				for _, flag := range strings.Fields(flags) {
+					m.flags[flag] = true
+				}
+			}
+		case "UID MOVE":
+			if m := s.find(uid); m != nil {
+				m.mailbox = strings.Trim(strings.Fields(args[2])[1], `"`)
+			}
+		case "UID COPY":
+			if m := s.find(uid); m != nil {
+				s.nextUid++
+				copied := &fakeMessage{s.nextUid, m.body, strings.Trim(strings.Fields(args[2])[1], `"`), map[string]bool{}}
+				s.messages = append(s.messages, copied)
+			}
+		case "UID EXPUNGE":
+			s.expunge(uid)
+		case "EXPUNGE":
+			s.expunge("")
+		case "IDLE":
+			fmt.Fprint(conn, "+ idling\r\n")
+			s.idle = conn
+			s.Unlock()
+			if !in.Scan() || in.Text() != "DONE" {
+				return
+			}
+			s.Lock()
+			s.idle = nil
+		case "LOGOUT":
+			fmt.Fprint(conn, "* BYE\r\n")
+		default:
+			result = "BAD unknown command"
+		}
+		fmt.Fprintf(conn, "%s %s\r\n", tag, result)
+		s.Unlock()
+		if command == "LOGOUT" {
+			return
+		}
+	}
+}
+
+func (s *fakeMailServer) servePOP3(conn net.Conn) {
+	fmt.Fprint(conn, "+OK fake POP3 server ready\r\n")
+	deleted := map[int]bool{}
+	in := bufio.NewScanner(conn)
+	for in.Scan() {
+		args := strings.Fields(in.Text())
+		n, _ := strconv.Atoi(args[len(args)-1])
+
+		s.Lock()
+		switch strings.ToUpper(args[0]) {
+		case "CAPA":
+			fmt.Fprint(conn, "+OK\r\nUSER\r\nUIDL\r\n.\r\n")
+		case "USER":
+			fmt.Fprint(conn, "+OK\r\n")
+		case "PASS":
+			s.logins++
+			if in.Text() == `PASS s3"cret` {
+				fmt.Fprint(conn, "+OK logged in\r\n")
+			} else {
+				fmt.Fprint(conn, "-ERR invalid credentials\r\n")
+			}
+		case "UIDL":
+			fmt.Fprint(conn, "+OK\r\n")
+			for i, m := range s.messages {
+				fmt.Fprintf(conn, "%d %d\r\n", i+1, m.uid)
+			}
+			fmt.Fprint(conn, ".\r\n")
+		case "RETR":
+			s.fetched++
+			body := strings.Replace(s.messages[n-1].body, "\n.", "\n..", -1)
+			fmt.Fprintf(conn, "+OK\r\n%s\r\n.\r\n", strings.TrimRight(body, "\r\n"))
+		case "DELE":
+			deleted[n] = true
+			fmt.Fprint(conn, "+OK\r\n")
+		case "QUIT":
+			kept := []*fakeMessage{}
+			for i, m := range s.messages {
+				if !deleted[i+1] {
+					kept = append(kept, m)
+				}
+			}
+			s.messages = kept
+			fmt.Fprint(conn, "+OK bye\r\n")
+			s.Unlock()
+			return
+		default:
+			fmt.Fprint(conn, "-ERR unknown command\r\n")
+		}
+		s.Unlock()
+	}
+}
+
+// waitFor waits until the state of the server matches.
+func waitFor(t *testing.T, s *fakeMailServer, expected string) {
+	for i := 0; s.state() != expected && i < 200; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if state := s.state(); state != expected {
+		t.Errorf("Expected messages %q, got %q", expected, state)
+	}
+}
+
+// pollTestMessages returns a voicemail that is already stored, an email
+// that is not from the FRITZ!Box and one without the details of the
+// call.
+func pollTestMessages(t *testing.T, db Database) []string {
+	data, err := ioutil.ReadFile("smtp_test.data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	voicemail, err := extractCall(string(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ImportVoicemail(voicemail, []byte("audio")); err != nil {
+		t.Fatal(err)
+	}
+	broken := strings.Replace(string(data), "Content-Type: text/html", "Content-Type: text/enriched", 1)
+	return []string{string(data), "Subject: Hello\r\n\r\nNot a voicemail\r\n", broken}
+}
+
+func TestPollIMAP(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "voicemail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	db := OpenDatabase(path.Join(tempDir, "voicemail.sqlite"), tempDir)
+	defer db.Close()
+	msgs := pollTestMessages(t, db)
+
+	for _, test := range []struct {
+		capabilities string
+		moveTo       string
+		expected     string
+	}{
+		{"IMAP4rev1 IDLE MOVE", "Voicemail", `Voicemail:,INBOX:,INBOX:\Seen \Flagged`},
+		{"IMAP4rev1 IDLE UIDPLUS", "Voicemail", `INBOX:,INBOX:\Seen \Flagged,Voicemail:`},
+		{"IMAP4rev1", "", `INBOX:\Seen,INBOX:,INBOX:\Seen \Flagged`},
+	} {
+		server := &fakeMailServer{t: t, capabilities: test.capabilities}
+		for _, msg := range msgs {
+			server.add("INBOX", msg)
+		}
+		l := server.listen(server.serveIMAP)
+		stop := StartPoll(db, PollConfig{Protocol: "imap", Server: l.Addr().String(),
+			Username: "voicemail", Password: `s3"cret`, Insecure: true, MoveTo: test.moveTo,
+			Interval: 20 * time.Millisecond})
+
+		waitFor(t, server, test.expected)
+		if strings.Contains(test.capabilities, "IDLE") {
+			// The new message is reported while idling.
+			server.add("INBOX", msgs[0])
+			waitFor(t, server, test.expected+",Voicemail:")
+		}
+		stop()
+		l.Close()
+	}
+}
+
+func TestPollPOP3(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "voicemail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	db := OpenDatabase(path.Join(tempDir, "voicemail.sqlite"), tempDir)
+	defer db.Close()
+
+	server := &fakeMailServer{t: t}
+	msgs := pollTestMessages(t, db)
+	for _, msg := range msgs {
+		server.add("INBOX", msg)
+	}
+	l := server.listen(server.servePOP3)
+	defer l.Close()
+	stop := StartPoll(db, PollConfig{Protocol: "pop3", Server: l.Addr().String(),
+		Username: "voicemail", Password: `s3"cret`, Insecure: true, Interval: 20 * time.Millisecond})
+	defer stop()
+
+	// The stored voicemail is deleted, the others are fetched only once.
+	waitFor(t, server, "INBOX:,INBOX:")
+	server.add("INBOX", msgs[0])
+	time.Sleep(100 * time.Millisecond)
+	waitFor(t, server, "INBOX:,INBOX:")
+	server.Lock()
+	fetched := server.fetched
+	server.Unlock()
+	if fetched != 4 {
+		t.Errorf("Expected 4 messages fetched, got %d", fetched)
+	}
+
+	// Skipped messages that are no longer on the server are forgotten.
+	p := &poller{
+		db: db,
+		config: PollConfig{Protocol: "pop3", Server: l.Addr().String(),
+			Username: "voicemail", Password: `s3"cret`, Insecure: true},
+		log:     logger,
+		stop:    make(chan struct{}),
+		skipped: map[string]bool{"gone": true},
+	}
+	if err := p.pollPOP3(); err != nil {
+		t.Fatal(err)
+	}
+	if p.skipped["gone"] || len(p.skipped) != 2 {
+		t.Errorf("Unexpected skipped messages %v", p.skipped)
+	}
+}
+
+func TestPollStorageFailure(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "voicemail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	data, err := ioutil.ReadFile("smtp_test.data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The closed database fails like a locked one.
+	db := OpenDatabase(path.Join(tempDir, "voicemail.sqlite"), tempDir)
+	db.Close()
+
+	for _, protocol := range []string{"imap", "pop3"} {
+		server := &fakeMailServer{t: t, capabilities: "IMAP4rev1"}
+		server.add("INBOX", string(data))
+		l := server.listen(server.serveIMAP)
+		if protocol == "pop3" {
+			l.Close()
+			l = server.listen(server.servePOP3)
+		}
+		stop := StartPoll(db, PollConfig{Protocol: protocol, Server: l.Addr().String(),
+			Username: "voicemail", Password: `s3"cret`, Insecure: true, Interval: 20 * time.Millisecond})
+
+		// The message is kept and fetched again.
+		for i := 0; i < 200; i++ {
+			server.Lock()
+			fetched := server.fetched
+			server.Unlock()
+			if fetched >= 2 {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		stop()
+		l.Close()
+		if state := server.state(); state != "INBOX:" || server.fetched < 2 {
+			t.Errorf("%s: unexpected messages %q, fetched %d times", protocol, state, server.fetched)
+		}
+	}
+}
+
+func TestPollCleartext(t *testing.T) {
+	server := &fakeMailServer{t: t, capabilities: "IMAP4rev1"}
+	for _, protocol := range []string{"imap", "pop3"} {
+		serve := server.serveIMAP
+		if protocol == "pop3" {
+			serve = server.servePOP3
+		}
+		l := server.listen(serve)
+		p := &poller{config: PollConfig{Protocol: protocol, Server: l.Addr().String(),
+			Username: "voicemail", Password: `s3"cret`}, stop: make(chan struct{})}
+
+		var err error
+		if protocol == "imap" {
+			_, err = p.dialIMAP()
+		} else {
+			_, err = p.dialPOP3()
+		}
+		l.Close()
+		if err != errCleartext {
+			t.Errorf("%s: expected %v, got %v", protocol, errCleartext, err)
+		}
+	}
+	if server.logins != 0 {
+		t.Errorf("Password sent %d times without encryption", server.logins)
+	}
+}
+
+func TestParsePollConfig(t *testing.T) {
+	config, err := ParsePollConfig([]byte(`{"protocol": "imap", "server": "imap.example.com:993",
+		"tls": true, "username": "voicemail", "move_to": "Voicemail", "interval": "5m"}`))
+	if err != nil || config.Interval != 5*time.Minute || config.MoveTo != "Voicemail" {
+		t.Errorf("Unexpected config %+v: %v", config, err)
+	}
+
+	for _, invalid := range []string{
+		`{"protocol": "smtp", "server": "mail.example.com:25", "username": "voicemail"}`,
+		`{"protocol": "imap", "server": "imap.example.com", "username": "voicemail"}`,
+		`{"protocol": "imap", "server": "imap.example.com:143"}`,
+		`{"protocol": "pop3", "server": "pop.example.com:110", "username": "voicemail", "move_to": "Voicemail"}`,
+		`{"protocol": "imap", "server": "imap.example.com:143", "username": "voicemail", "password": "a\r\nb"}`,
+		`{"protocol": "imap", "server": "imap.example.com:143", "username": "voicemail", "interval": "often"}`,
+	} {
+		if _, err := ParsePollConfig([]byte(invalid)); err == nil {
+			t.Errorf("Invalid config accepted: %s", invalid)
+		}
+	}
+}
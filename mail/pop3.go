@@ -0,0 +1,175 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// pop3Client is a minimal POP3 client.
+type pop3Client struct {
+	*mailConn
+}
+
+// command sends a command and returns the text of the +OK response.
+func (c *pop3Client) command(command string) (string, error) {
+	if err := c.writeLine(command); err != nil {
+		return "", err
+	}
+	return c.status()
+}
+
+func (c *pop3Client) status() (string, error) {
+	line, err := c.readLine()
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(line, "+OK") {
+		return "", errors.New(strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+	}
+	return strings.TrimSpace(strings.TrimPrefix(line, "+OK")), nil
+}
+
+// lines reads a multi-line response up to the line with a single dot.
+func (c *pop3Client) lines() ([]string, error) {
+	lines := []string{}
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if line == "." {
+			return lines, nil
+		}
+		lines = append(lines, strings.TrimPrefix(line, "."))
+	}
+}
+
+// stls continues the connection with TLS if the server lists STLS in
+// its capabilities.  CAPA is optional, so its failure is ignored.
+func (c *pop3Client) stls(config PollConfig) error {
+	var capabilities []string
+	if _, err := c.command("CAPA"); err == nil {
+		if capabilities, err = c.lines(); err != nil {
+			return err
+		}
+	}
+	for _, capability := range capabilities {
+		if strings.ToUpper(capability) == "STLS" {
+			if _, err := c.command("STLS"); err != nil {
+				return err
+			}
+			return c.startTLS(config.Server)
+		}
+	}
+	if !config.Insecure {
+		return errCleartext
+	}
+	return nil
+}
+
+// dialPOP3 connects and logs in.
+func (p *poller) dialPOP3() (*pop3Client, error) {
+	conn, err := p.dial()
+	if err != nil {
+		return nil, err
+	}
+	c := &pop3Client{conn}
+
+	_, err = c.status()
+	if err == nil && !p.config.TLS {
+		err = c.stls(p.config)
+	}
+	if err == nil {
+		_, err = c.command("USER " + p.config.Username)
+	}
+	if err == nil {
+		_, err = c.command("PASS " + p.config.Password)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// pollPOP3 processes the messages in the mailbox.  Stored messages are
+// deleted.  Messages that could not be processed and those not sent by
+// the FRITZ!Box are left on the server and skipped by later polls.  If
+// storing a message fails, the poll ends and it is fetched again.
+func (p *poller) pollPOP3() error {
+	c, err := p.dialPOP3()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	p.log.Debug("Connected to mail server", "protocol", "pop3")
+
+	if _, err := c.command("UIDL"); err != nil {
+		return err
+	}
+	list, err := c.lines()
+	if err != nil {
+		return err
+	}
+	// Forget the skipped messages that were removed from the server.
+	present := map[string]bool{}
+	for _, entry := range list {
+		if fields := strings.Fields(entry); len(fields) == 2 {
+			present[fields[1]] = true
+		}
+	}
+	for uid := range p.skipped {
+		if !present[uid] {
+			delete(p.skipped, uid)
+		}
+	}
+
+	var deliverErr error
+	for _, entry := range list {
+		fields := strings.Fields(entry)
+		if len(fields) != 2 || p.skipped[fields[1]] {
+			continue
+		}
+		if p.stopped() {
+			return errStopped
+		}
+		number, uid := fields[0], fields[1]
+
+		if _, err := c.command("RETR " + number); err != nil {
+			return err
+		}
+		lines, err := c.lines()
+		if err != nil {
+			return err
+		}
+		msg := strings.Join(lines, "\r\n") + "\r\n"
+		if !isFritzBoxMessage(msg) {
+			p.skipped[uid] = true
+			continue
+		}
+
+		log := p.log.With("uid", uid, "message_id", messageId(msg))
+		if err := p.deliver(log, msg); errors.Is(err, errUnparsable) {
+			p.skipped[uid] = true
+			continue
+		} else if err != nil {
+			// The message is fetched again by the next poll.
+			deliverErr = fmt.Errorf("unable to store message %s: %v", uid, err)
+			break
+		}
+		if _, err := c.command("DELE " + number); err != nil {
+			return err
+		}
+	}
+
+	// The messages are deleted when the session ends.
+	if _, err := c.command("QUIT"); err != nil {
+		return err
+	}
+	if deliverErr != nil {
+		return deliverErr
+	}
+	polls.Inc("ok")
+	return nil
+}
@@ -8,19 +8,32 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	log "log"
 	"mime/multipart"
 	"net"
 	"net/mail"
 	"os/exec"
 	"strings"
+	"time"
+	"unicode/utf8"
 	"../external/go-qprintable"
 
+	"../metrics"
 	"../model"
 	. "../utils"
 )
 
-var logger *log.Logger = Logger("mail")
+var logger = Logger("mail")
+
+var (
+	smtpSessions = metrics.NewCounter("voicemail_smtp_sessions_total",
+		"SMTP connections accepted.")
+	messages = metrics.NewCounter("voicemail_messages_total",
+		"Received messages by result: accepted, rejected or incomplete.", "result")
+	parseFailures = metrics.NewCounter("voicemail_parse_failures_total",
+		"Messages that could not be processed by reason.", "reason")
+	transcodeDuration = metrics.NewHistogram("voicemail_transcode_duration_seconds",
+		"Time to convert the audio of a voicemail to MP3.", metrics.DurationBuckets)
+)
 
 func handleData(rd bufio.Reader, out io.Writer) string {
 	// Stolen from go-smtpd:
@@ -51,6 +64,10 @@ func handleData(rd bufio.Reader, out io.Writer) string {
 	return buf.String()
 }
 
+// errNoMessage is returned for sessions that end without a message, like
+// those of CheckListener.
+var errNoMessage = errors.New("No message received!")
+
 func receiveMessage(in bufio.Reader, out io.Writer) (string, error) {
 	// HACK: Here we should negotiate with the SMTP
 	// client and tell it what capabilities we have.
@@ -73,7 +90,7 @@ func receiveMessage(in bufio.Reader, out io.Writer) (string, error) {
 		case "DATA":
 			return handleData(in, out), nil
 		case "QUIT":
-			return "", errors.New("No message received!")
+			return "", errNoMessage
 		}
 	}
 }
@@ -113,19 +130,44 @@ func extractCall(msg string) (model.Voicemail, error) {
 	}
 
 	html := unquote(metadata)
+	if !utf8.ValidString(html) {
+		// Older FRITZ!Box versions send ISO-8859-1.
+		runes := make([]rune, len(html))
+		for i := 0; i < len(html); i++ {
+			runes[i] = rune(html[i])
+		}
+		html = string(runes)
+	}
 	return ParseHtml(strings.NewReader(html))
 }
 
-func extractVoicemailAudio(msg string) ([]byte, error) {
+// extractAudio returns the WAV audio of a message.
+func extractAudio(msg string) (io.Reader, error) {
 	voicemailB64, err := extractPart(msg, "audio/x-wav",
 		"==AVM_Fritz_Box==multipart/mixed==0==")
 	if err != nil {
 		return nil, err
 	}
-	voicemail := base64.NewDecoder(base64.StdEncoding,
+	return base64.NewDecoder(base64.StdEncoding,
 		bytes.NewBuffer(bytes.Replace(
-			voicemailB64, []byte("\r\n"), []byte{}, -1)))
+			voicemailB64, []byte("\r\n"), []byte{}, -1))), nil
+}
+
+func extractVoicemailAudio(msg string) ([]byte, error) {
+	voicemail, err := extractAudio(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	defer func() {
+		transcodeDuration.Observe(time.Since(start).Seconds())
+	}()
+	return transcode(voicemail)
+}
 
+// transcode converts WAV audio to MP3.
+func transcode(voicemail io.Reader) ([]byte, error) {
 	// For the voicemail to be useful, it needs to be a MP3 file.
 	// The voicemails are encoded with the aLaw/uLaw codec.
 	// lame does not know about it, so we use mplayer to decode
@@ -166,51 +208,100 @@ func extractVoicemailAudio(msg string) ([]byte, error) {
 	return bytes, nil
 }
 
+// messageId returns the Message-Id header of a message.
+func messageId(rawMsg string) string {
+	msg, err := mail.ReadMessage(strings.NewReader(rawMsg))
+	if err != nil {
+		return ""
+	}
+	return msg.Header.Get("Message-Id")
+}
+
 func ProcessMessage(db model.Database, conn net.Conn) (model.Voicemail, []byte, error) {
+	log := logger.With("remote", conn.RemoteAddr())
 	in := bufio.NewReader(conn)
 	msg, err := receiveMessage(*in, conn)
-	if err != nil {
-		logger.Print("Message not received")
+	if err == errNoMessage {
+		log.Debug("Session ended without message")
+		return model.Voicemail{}, nil, err
+	} else if err != nil {
+		log.Warn("Message not received", "error", err)
+		messages.Inc("incomplete")
 		return model.Voicemail{}, nil, err
 	}
+	return parseMessage(db, log.With("message_id", messageId(msg)), msg)
+}
 
+// parseMessage extracts the call and the converted audio from a
+// message.  Failures are logged, counted and reported.
+func parseMessage(db model.Database, log *Log, msg string) (model.Voicemail, []byte, error) {
 	voicemail, err := extractCall(msg)
 	if err != nil {
-		logger.Print("Could not extract message")
+		log.Error("Could not extract message", "error", err)
+		parseFailures.Inc("metadata")
+		messages.Inc("rejected")
+		db.ReportFailure(voicemail, err)
 		return model.Voicemail{}, nil, err
 	}
-	logger.Print("Received new voicemail ", voicemail)
+	log.Info("Received new voicemail", "caller", voicemail.Caller, "called", voicemail.Called,
+		"date", voicemail.Date.Format(time.RFC3339), "duration", voicemail.Duration)
 
 	voicemailAudio, err := extractVoicemailAudio(msg)
 	if err != nil {
-		logger.Print("Could not extract audio")
+		log.Error("Could not extract audio", "error", err)
+		parseFailures.Inc("audio")
+		messages.Inc("rejected")
 		db.DumpRawMessage(voicemail, []byte(msg))
+		db.ReportFailure(voicemail, err)
 		return model.Voicemail{}, nil, err
 	}
 
 	return voicemail, voicemailAudio, nil
 }
 
-func Serve(l net.Listener, db model.Database) {
+// storeVoicemail adds a voicemail returned by parseMessage.
+func storeVoicemail(db model.Database, log *Log, voicemail model.Voicemail, voicemailAudio []byte) error {
+	if err := db.AddVoicemail(voicemail, voicemailAudio); err != nil {
+		log.Error("Unable to save to database", "error", err)
+		db.ReportFailure(voicemail, err)
+		messages.Inc("rejected")
+		return err
+	}
+	messages.Inc("accepted")
+	return nil
+}
+
+// Serve receives voicemails one at a time until the listener is
+// closed.  It returns after the voicemail being received at that time
+// has been stored.
+func Serve(l net.Listener, db model.Database) error {
 	defer l.Close()
+	addr := l.Addr()
+	listeners.Lock()
+	listeners.addrs[addr]++
+	listeners.Unlock()
+	defer func() {
+		listeners.Lock()
+		if listeners.addrs[addr]--; listeners.addrs[addr] == 0 {
+			delete(listeners.addrs, addr)
+		}
+		listeners.Unlock()
+	}()
 
 	for {
 		conn, err := l.Accept()
-		if err != nil {
-			logger.Fatal(err)
-			continue
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		} else if err != nil {
+			return err
 		}
 
-		logger.Print("Incoming voicemail from ", conn.RemoteAddr(), "?")
+		log := logger.With("remote", conn.RemoteAddr())
+		log.Debug("Incoming connection")
+		smtpSessions.Inc()
 		voicemail, voicemailAudio, err := ProcessMessage(db, conn)
-		if err != nil {
-			logger.Print("Unable to process voicemail: ", err)
-		} else {
-			if err := db.AddVoicemail(voicemail, voicemailAudio); err != nil {
-				logger.Print("Unable to save to database: ", err)
-			} else {
-				logger.Print("Save to database successful.")
-			}
+		if err == nil {
+			storeVoicemail(db, log, voicemail, voicemailAudio)
 		}
 		conn.Close()
 	}
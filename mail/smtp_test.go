@@ -1,11 +1,13 @@
 package mail
 
 import (
+	"errors"
 	"io/ioutil"
 	"net"
 	"net/smtp"
 	"os"
 	"path"
+	"strings"
 	"testing"
 	"time"
 
@@ -66,7 +68,9 @@ func TestReceive(t *testing.T) {
 		t.Error(err)
 	}
 
-	voicemail, _, err := ProcessMessage(conn)
+	db := OpenDatabase(path.Join(tempDir, "voicemail.sqlite"), tempDir)
+	defer db.Close()
+	voicemail, _, err := ProcessMessage(db, conn)
 	if err != nil {
 		t.Error(err)
 	}
@@ -88,3 +92,205 @@ func TestReceive(t *testing.T) {
 		t.Errorf("Voicemail garbled: expected: %v, actual: %v\n", referenceVoicemail, voicemail)
 	}
 }
+
+func TestCheckListener(t *testing.T) {
+	if err := CheckListener(); err == nil {
+		t.Error("Listener reported before serving")
+	}
+
+	tempDir, err := ioutil.TempDir("", "voicemail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := OpenDatabase(path.Join(tempDir, "voicemail.sqlite"), tempDir)
+	defer db.Close()
+	done := make(chan error)
+	go func() {
+		done <- Serve(l, db)
+	}()
+	for i := 0; CheckListener() != nil && i < 100; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if err := CheckListener(); err != nil {
+		t.Error(err)
+	}
+
+	l.Close()
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+	if err := CheckListener(); err == nil {
+		t.Error("Listener reported after closing")
+	}
+
+	// A listener that nobody accepts on does not greet, a closed one
+	// refuses connections.
+	listenerCheckTimeout = 100 * time.Millisecond
+	defer func() { listenerCheckTimeout = 3 * time.Second }()
+	stuck, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stuck.Close()
+	closed, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed.Close()
+	for _, addr := range []net.Addr{stuck.Addr(), closed.Addr()} {
+		listeners.Lock()
+		listeners.addrs[addr] = 1
+		listeners.Unlock()
+		if err := CheckListener(); err == nil {
+			t.Errorf("Listener on %v reported", addr)
+		}
+		listeners.Lock()
+		delete(listeners.addrs, addr)
+		listeners.Unlock()
+	}
+}
+
+func TestCachedCheck(t *testing.T) {
+	runs := 0
+	c := &cachedCheck{interval: time.Hour, check: func() error {
+		runs++
+		return errors.New("failed")
+	}}
+	for i := 0; i < 3; i++ {
+		if err := c.run(); err == nil || err.Error() != "failed" {
+			t.Errorf("Unexpected result %v", err)
+		}
+	}
+	if runs != 1 {
+		t.Errorf("Check run %d times within the interval", runs)
+	}
+
+	c.interval = 0
+	c.run()
+	if runs != 2 {
+		t.Errorf("Check not run again after the interval")
+	}
+
+	if audio := testAudio(); len(audio) != 2044 || string(audio[36:40]) != "data" {
+		t.Errorf("Invalid test audio header %q", audio[:44])
+	}
+}
+
+func TestParseHtml(t *testing.T) {
+	data, err := ioutil.ReadFile("smtp_test.data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The table of older FRITZ!Box versions
+	voicemail, err := extractCall(string(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := time.Date(2009, 10, 22, 11, 35, 0, 0, time.Local)
+	if voicemail.Caller != "5552341222" || voicemail.Called != "12312234" ||
+		!voicemail.Date.Equal(expected) || voicemail.Duration != 3*time.Second {
+		t.Errorf("Unexpected voicemail %+v", voicemail)
+	}
+
+	voicemail, err = ParseHtml(strings.NewReader(`<table>
+		<tr><td>Anruf von:</td><td>030123456</td></tr>
+		<tr><td>f&uuml;r die Rufnummer:</td><td>12312234</td></tr>
+		<tr><td>Datum:</td><td>01.03.24</td></tr>
+		<tr><td>Uhrzeit:</td><td>08:15 Uhr</td></tr>
+		<tr><td>Aufnahmel&auml;nge:</td><td>01:02</td></tr>
+	</table>`))
+	expected = time.Date(2024, 3, 1, 8, 15, 0, 0, time.Local)
+	if err != nil || voicemail.Caller != "030123456" || voicemail.Called != "12312234" ||
+		!voicemail.Date.Equal(expected) || voicemail.Duration != 62*time.Second {
+		t.Errorf("Unexpected voicemail %+v: %v", voicemail, err)
+	}
+}
+
+func TestReadMessages(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "voicemail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		"Maildir/new/1.host":     "Subject: 1\n",
+		"Maildir/cur/2.host:2,S": "Subject: 2\n",
+		"Maildir/tmp/3.host":     "Subject: 3\n",
+		"eml/b.eml":              "Subject: b\n",
+		"eml/a.eml":              "Subject: a\n",
+		"eml/.c.eml":             "Subject: c\n",
+		"eml/notes.txt":          "Notes\n",
+		"mbox":                   "From fritzbox Thu Oct 22 11:35:00 2009\nSubject: 1\n\n>From here\n\nFrom fritzbox Thu Oct 22 11:36:00 2009\nSubject: 2\n",
+		"single.eml":             "Subject: single\n\nFrom here\n",
+	}
+	for name, content := range files {
+		name = path.Join(tempDir, name)
+		if err := os.MkdirAll(path.Dir(name), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(name, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for source, expected := range map[string][]string{
+		"Maildir":    {"new/1.host", "Subject: 1\n", "cur/2.host:2,S", "Subject: 2\n"},
+		"eml":        {"a.eml", "Subject: a\n", "b.eml", "Subject: b\n"},
+		"mbox":       {":1", "Subject: 1\n\nFrom here\n\n", ":2", "Subject: 2\n"},
+		"single.eml": {"", "Subject: single\n\nFrom here\n"},
+	} {
+		source = path.Join(tempDir, source)
+		read := []string{}
+		err := ReadMessages(source, func(name string, msg string) error {
+			read = append(read, strings.TrimPrefix(strings.TrimPrefix(name, source), "/"), msg)
+			return nil
+		})
+		if err != nil || strings.Join(read, "|") != strings.Join(expected, "|") {
+			t.Errorf("%s: unexpected messages %q: %v", source, read, err)
+		}
+	}
+
+	if err := ReadMessages(path.Join(tempDir, "missing"), nil); !os.IsNotExist(err) {
+		t.Errorf("Unexpected error for a missing source: %v", err)
+	}
+}
+
+func TestImportMessage(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "voicemail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	db := OpenDatabase(path.Join(tempDir, "voicemail.sqlite"), tempDir)
+	defer db.Close()
+	data, err := ioutil.ReadFile("smtp_test.data")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	voicemail, err := ImportMessage(db, string(data), true)
+	if err != nil || voicemail.Caller != "5552341222" {
+		t.Fatalf("Unexpected voicemail %+v: %v", voicemail, err)
+	}
+	if page, err := db.GetPage(Query{Folder: AllFolders}); err != nil || len(page.Voicemails) != 0 {
+		t.Errorf("Dry run stored voicemails: %v", err)
+	}
+
+	if _, err := db.ImportVoicemail(voicemail, []byte("audio")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportMessage(db, string(data), true); err != ErrDuplicate {
+		t.Errorf("Duplicate not detected: %v", err)
+	}
+	if _, err := ImportMessage(db, "Subject: no voicemail\n\n", true); err == nil {
+		t.Error("Message without a voicemail accepted")
+	}
+}
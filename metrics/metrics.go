@@ -0,0 +1,235 @@
+// Package metrics collects counters and histograms and exposes them in
+// the Prometheus text format.
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// metric is a registered counter, histogram or gauge.
+type metric interface {
+	write(w io.Writer)
+}
+
+var (
+	registry     = []metric{}
+	registryLock sync.Mutex
+)
+
+func register(m metric) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	registry = append(registry, m)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labelSet formats label names and values as {name="value",...}.
+func labelSet(names []string, values []string, extra ...string) string {
+	if len(names)+len(extra) == 0 {
+		return ""
+	}
+	pairs := []string{}
+	for i, name := range names {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		pairs = append(pairs, name+`="`+labelEscaper.Replace(value)+`"`)
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+`="`+labelEscaper.Replace(extra[i+1])+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func writeHeader(w io.Writer, name string, help string, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// Counter is a value that only increases, optionally partitioned by
+// labels.
+type Counter struct {
+	sync.Mutex
+	name   string
+	help   string
+	labels []string
+	values map[string]float64
+	order  [][]string
+}
+
+// NewCounter registers a counter.  Inc and Add take the values of the
+// labels in the same order.
+func NewCounter(name string, help string, labels ...string) *Counter {
+	c := &Counter{name: name, help: help, labels: labels, values: map[string]float64{}}
+	if len(labels) == 0 {
+		c.Add(0)
+	}
+	register(c)
+	return c
+}
+
+// Inc increments the counter by 1.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increases the counter.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	key := strings.Join(labelValues, "\xff")
+	c.Lock()
+	defer c.Unlock()
+	if _, ok := c.values[key]; !ok {
+		c.order = append(c.order, labelValues)
+	}
+	c.values[key] += v
+}
+
+// Value returns the current value for the label values.
+func (c *Counter) Value(labelValues ...string) float64 {
+	c.Lock()
+	defer c.Unlock()
+	return c.values[strings.Join(labelValues, "\xff")]
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.Lock()
+	defer c.Unlock()
+	writeHeader(w, c.name, c.help, "counter")
+	for _, labelValues := range sortedLabels(c.order) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, labelSet(c.labels, labelValues),
+			formatValue(c.values[strings.Join(labelValues, "\xff")]))
+	}
+}
+
+func sortedLabels(order [][]string) [][]string {
+	sorted := append([][]string{}, order...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return strings.Join(sorted[i], "\xff") < strings.Join(sorted[j], "\xff")
+	})
+	return sorted
+}
+
+// Histogram counts observations, e.g. durations, in buckets.
+type Histogram struct {
+	sync.Mutex
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+	series  map[string]*histogramSeries
+	order   [][]string
+}
+
+type histogramSeries struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// DurationBuckets are bucket bounds in seconds for operations that take
+// between milliseconds and a minute.
+var DurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60}
+
+// NewHistogram registers a histogram with the given upper bucket
+// bounds.
+func NewHistogram(name string, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{name: name, help: help, labels: labels, buckets: buckets,
+		series: map[string]*histogramSeries{}}
+	register(h)
+	return h
+}
+
+// Observe adds an observation.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	key := strings.Join(labelValues, "\xff")
+	h.Lock()
+	defer h.Unlock()
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+		h.order = append(h.order, labelValues)
+	}
+	for i, bound := range h.buckets {
+		if v <= bound {
+			s.counts[i]++
+		}
+	}
+	s.sum += v
+	s.count++
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.Lock()
+	defer h.Unlock()
+	writeHeader(w, h.name, h.help, "histogram")
+	for _, labelValues := range sortedLabels(h.order) {
+		s := h.series[strings.Join(labelValues, "\xff")]
+		for i, bound := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name,
+				labelSet(h.labels, labelValues, "le", formatValue(bound)), s.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, labelSet(h.labels, labelValues, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, labelSet(h.labels, labelValues), formatValue(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, labelSet(h.labels, labelValues), s.count)
+	}
+}
+
+// gaugeFunc is a value that is read when the metrics are written.
+type gaugeFunc struct {
+	name string
+	help string
+	f    func() (float64, error)
+}
+
+// NewGaugeFunc registers a gauge whose value is returned by f.  The
+// gauge is left out if f fails.
+func NewGaugeFunc(name string, help string, f func() (float64, error)) {
+	register(gaugeFunc{name, help, f})
+}
+
+func (g gaugeFunc) write(w io.Writer) {
+	v, err := g.f()
+	if err != nil {
+		return
+	}
+	writeHeader(w, g.name, g.help, "gauge")
+	fmt.Fprintf(w, "%s %s\n", g.name, formatValue(v))
+}
+
+// WriteTo writes all metrics in the Prometheus text format.
+func WriteTo(w io.Writer) {
+	registryLock.Lock()
+	metrics := append([]metric{}, registry...)
+	registryLock.Unlock()
+
+	for _, m := range metrics {
+		m.write(w)
+	}
+}
+
+// Handler serves the metrics.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		WriteTo(&buf)
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Write(buf.Bytes())
+	})
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	counter := NewCounter("test_total", "Test counter.", "result")
+	counter.Inc("ok")
+	counter.Add(2, `a "b"`)
+	counter.Inc("ok")
+	if counter.Value("ok") != 2 {
+		t.Errorf("Unexpected value %v", counter.Value("ok"))
+	}
+
+	histogram := NewHistogram("test_seconds", "Test histogram.", []float64{.1, 1})
+	histogram.Observe(.5)
+	histogram.Observe(2)
+
+	NewGaugeFunc("test_gauge", "Test gauge.", func() (float64, error) { return 1.5, nil })
+	NewGaugeFunc("test_failing", "Failing gauge.", func() (float64, error) { return 0, errors.New("failed") })
+
+	var buf bytes.Buffer
+	WriteTo(&buf)
+	expected := `# HELP test_total Test counter.
+# TYPE test_total counter
+test_total{result="a \"b\""} 2
+test_total{result="ok"} 2
+# HELP test_seconds Test histogram.
+# TYPE test_seconds histogram
+test_seconds_bucket{le="0.1"} 0
+test_seconds_bucket{le="1"} 1
+test_seconds_bucket{le="+Inf"} 2
+test_seconds_sum 2.5
+test_seconds_count 2
+# HELP test_gauge Test gauge.
+# TYPE test_gauge gauge
+test_gauge 1.5
+`
+	if buf.String() != expected {
+		t.Errorf("Unexpected output:\n%s", buf.String())
+	}
+	if strings.Contains(buf.String(), "test_failing") {
+		t.Error("Failing gauge written")
+	}
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Delivery records an attempt to notify an external system about a
+// voicemail.
+type Delivery struct {
+	Id        int
+	Voicemail int
+	// Channel is the kind of notification, e.g. "webhook".
+	Channel string
+	// Target is the receiver, e.g. the URL of a webhook.
+	Target  string
+	Attempt int
+	Success bool
+	Error   string
+	Date    time.Time
+}
+
+// deliveryLogAge is how long deliveries are kept in the log.
+const deliveryLogAge = 30 * 24 * time.Hour
+
+// LogDelivery adds a delivery attempt to the log and removes old
+// entries.
+func (db Database) LogDelivery(delivery Delivery) error {
+	if delivery.Date.IsZero() {
+		delivery.Date = time.Now()
+	}
+
+	errorChannel := make(chan error)
+
+	db.channel <- func(conn *sql.DB) {
+		if _, err := conn.Exec("DELETE FROM delivery WHERE julianday(date) < julianday(?)",
+			time.Now().Add(-deliveryLogAge).Format(dateFormat)); err != nil {
+			errorChannel <- err
+			return
+		}
+
+		_, err := conn.Exec(`INSERT INTO delivery (voicemail, channel, target, attempt, success, error, date)
+                                     VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			delivery.Voicemail, delivery.Channel, delivery.Target, delivery.Attempt,
+			delivery.Success, delivery.Error, delivery.Date.Format(dateFormat))
+		errorChannel <- err
+	}
+
+	return <-errorChannel
+}
+
+// GetDeliveries returns the newest entries of the delivery log.  A
+// limit below 1 returns all of them.
+func (db Database) GetDeliveries(limit int) ([]Delivery, error) {
+	if limit < 1 {
+		limit = -1
+	}
+
+	errorChannel := make(chan error)
+	deliveries := []Delivery{}
+
+	db.channel <- func(conn *sql.DB) {
+		rows, err := conn.Query(`SELECT id, voicemail, channel, target, attempt, success, error, date
+                                         FROM delivery ORDER BY id DESC LIMIT ?`, limit)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var delivery Delivery
+			var date string
+			if err := rows.Scan(&delivery.Id, &delivery.Voicemail, &delivery.Channel, &delivery.Target,
+				&delivery.Attempt, &delivery.Success, &delivery.Error, &date); err != nil {
+				errorChannel <- err
+				return
+			}
+			if delivery.Date, err = time.Parse(dateFormat, date); err != nil {
+				errorChannel <- err
+				return
+			}
+			deliveries = append(deliveries, delivery)
+		}
+		errorChannel <- rows.Err()
+	}
+
+	return deliveries, <-errorChannel
+}
@@ -0,0 +1,137 @@
+package model
+
+import (
+	"database/sql"
+	"sync"
+)
+
+// EventType tells how a voicemail changed.
+type EventType string
+
+const (
+	VoicemailAdded   EventType = "added"
+	VoicemailChanged EventType = "changed"
+	VoicemailPurged  EventType = "purged"
+	// VoicemailFailed reports a received voicemail that could not be
+	// processed or stored.
+	VoicemailFailed EventType = "failed"
+)
+
+// Event reports a change of a voicemail.  For VoicemailPurged,
+// Voicemail holds the state before it was removed.  For
+// VoicemailFailed, it holds what is known about the voicemail and
+// Error tells what went wrong.
+type Event struct {
+	Type      EventType
+	Voicemail Voicemail
+	Error     string
+	// Trashed marks the VoicemailChanged event of a voicemail that was
+	// moved into the trash.
+	Trashed bool
+}
+
+// eventBufferSize is the number of events buffered per subscriber.
+// Events for subscribers that fall further behind are dropped.
+const eventBufferSize = 64
+
+type eventBus struct {
+	sync.Mutex
+	subscribers map[chan Event]bool
+	// held collects the events while they are held back.
+	holding bool
+	held    []Event
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{subscribers: map[chan Event]bool{}}
+}
+
+// Subscribe returns a channel that receives an event for every change of
+// a voicemail in this process.  The returned function ends the
+// subscription and closes the channel.
+func (db Database) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, eventBufferSize)
+
+	db.events.Lock()
+	db.events.subscribers[ch] = true
+	db.events.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			db.events.Lock()
+			delete(db.events.subscribers, ch)
+			db.events.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// HoldEvents holds back all events until the returned function is
+// called, which publishes them to the subscribers at that time.  This
+// hands the events over from one set of subscribers to another without
+// losing or duplicating any.
+func (db Database) HoldEvents() func() {
+	db.events.Lock()
+	db.events.holding = true
+	db.events.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			db.events.Lock()
+			defer db.events.Unlock()
+			db.events.holding = false
+			for _, event := range db.events.held {
+				db.events.send(event)
+			}
+			db.events.held = nil
+		})
+	}
+}
+
+func (db Database) publish(event Event) {
+	db.events.Lock()
+	defer db.events.Unlock()
+
+	if db.events.holding {
+		db.events.held = append(db.events.held, event)
+		return
+	}
+	db.events.send(event)
+}
+
+// send passes an event to all subscribers.  The lock must be held.
+func (bus *eventBus) send(event Event) {
+	for ch := range bus.subscribers {
+		select {
+		case ch <- event:
+		default:
+			logger.Warn("Event subscriber too slow, event dropped",
+				"event", event.Type, "voicemail", event.Voicemail.Id)
+		}
+	}
+}
+
+// publishVoicemail reads a voicemail and publishes an event for it.  It
+// must be called from the database goroutine.
+func (db Database) publishVoicemail(conn *sql.DB, eventType EventType, id int) {
+	db.publishEvent(conn, Event{Type: eventType}, id)
+}
+
+// publishEvent adds the voicemail to an event and publishes it.
+func (db Database) publishEvent(conn *sql.DB, event Event, id int) {
+	voicemail, err := scanVoicemail(conn.QueryRow(
+		"SELECT "+voicemailColumns+" FROM voicemail WHERE id = ?", id))
+	if err != nil {
+		logger.Error("Unable to read voicemail for event", "event", event.Type, "voicemail", id, "error", err)
+		return
+	}
+	event.Voicemail = voicemail
+	db.publish(event)
+}
+
+// ReportFailure publishes a VoicemailFailed event.
+func (db Database) ReportFailure(voicemail Voicemail, err error) {
+	db.publish(Event{Type: VoicemailFailed, Voicemail: voicemail, Error: err.Error()})
+}
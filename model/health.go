@@ -0,0 +1,64 @@
+package model
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"syscall"
+	"time"
+)
+
+// ErrTimeout is returned if the database does not answer in time.
+var ErrTimeout = errors.New("database did not respond in time")
+
+// Ping checks that the database goroutine takes queries and the
+// database answers within timeout.
+func (db Database) Ping(timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	// The query may still run after the timeout.
+	errorChannel := make(chan error, 1)
+	select {
+	case db.channel <- func(conn *sql.DB) {
+		errorChannel <- conn.Ping()
+	}:
+	case <-timer.C:
+		return ErrTimeout
+	}
+
+	select {
+	case err := <-errorChannel:
+		return err
+	case <-timer.C:
+		return ErrTimeout
+	}
+}
+
+// CheckStorage checks that a file can be written to the storage
+// directory and that at least minFree bytes are available.
+func (db Database) CheckStorage(minFree uint64) error {
+	file, err := ioutil.TempFile(db.storageDir, ".healthcheck")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write([]byte("ok"))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	os.Remove(file.Name())
+	if err != nil {
+		return err
+	}
+
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(db.storageDir, &stat); err != nil {
+		return err
+	}
+	if free := uint64(stat.Bavail) * uint64(stat.Bsize); free < minFree {
+		return fmt.Errorf("only %d MB free in %s", free/1024/1024, db.storageDir)
+	}
+	return nil
+}
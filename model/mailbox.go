@@ -0,0 +1,198 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// Mailbox groups the voicemails left for a set of called numbers, e.g.
+// the MSNs of one answering machine.
+type Mailbox struct {
+	// Name identifies the mailbox in URLs, MQTT topics and on the
+	// command line.  It consists of letters, digits, ".", "-" and "_".
+	Name string `json:"name"`
+	// Title is shown in the web interface.
+	Title string `json:"title"`
+	// Numbers are the called numbers of the mailbox.  A mailbox
+	// without numbers receives all voicemails for numbers that are not
+	// listed in any other mailbox.
+	Numbers []string `json:"numbers"`
+	// Retention overrides the global retention policy for the voicemails
+	// in this mailbox if any of its rules is enabled.
+	Retention RetentionPolicy `json:"retention"`
+}
+
+// Mailboxes is the list of configured mailboxes.  Without mailboxes
+// all voicemails are shown in a single list.
+type Mailboxes []Mailbox
+
+// LoadMailboxes reads mailbox definitions from a JSON file containing
+// a list of mailboxes.
+func LoadMailboxes(file string) (Mailboxes, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	mailboxes, err := ParseMailboxes(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return mailboxes, nil
+}
+
+// ParseMailboxes reads mailbox definitions from a JSON list.
+func ParseMailboxes(data []byte) (Mailboxes, error) {
+	var mailboxes Mailboxes
+	if err := json.Unmarshal(data, &mailboxes); err != nil {
+		return nil, err
+	}
+
+	if err := mailboxes.Validate(); err != nil {
+		return nil, err
+	}
+
+	for i := range mailboxes {
+		if mailboxes[i].Title == "" {
+			mailboxes[i].Title = mailboxes[i].Name
+		}
+	}
+
+	return mailboxes, nil
+}
+
+// validMailboxName reports whether a name can be used in URLs and MQTT
+// topics without escaping.
+func validMailboxName(name string) bool {
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+			c == '.' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks that names are valid, that names and numbers are
+// unique and that there is at most one mailbox without numbers.
+func (m Mailboxes) Validate() error {
+	names := map[string]bool{}
+	numbers := map[string]string{}
+	catchAll := ""
+
+	for _, mailbox := range m {
+		if mailbox.Name == "" {
+			return fmt.Errorf("mailbox without name")
+		}
+		if !validMailboxName(mailbox.Name) {
+			return fmt.Errorf("invalid mailbox name %q", mailbox.Name)
+		}
+		if names[mailbox.Name] {
+			return fmt.Errorf("duplicate mailbox %q", mailbox.Name)
+		}
+		names[mailbox.Name] = true
+
+		if len(mailbox.Numbers) == 0 {
+			if catchAll != "" {
+				return fmt.Errorf("mailboxes %q and %q both have no numbers", catchAll, mailbox.Name)
+			}
+			catchAll = mailbox.Name
+		}
+
+		for _, number := range mailbox.Numbers {
+			if other, ok := numbers[number]; ok {
+				return fmt.Errorf("number %s is in mailboxes %q and %q", number, other, mailbox.Name)
+			}
+			numbers[number] = mailbox.Name
+		}
+	}
+
+	return nil
+}
+
+func (m Mailboxes) Get(name string) (Mailbox, bool) {
+	for _, mailbox := range m {
+		if mailbox.Name == name {
+			return mailbox, true
+		}
+	}
+	return Mailbox{}, false
+}
+
+// ForNumber returns the mailbox that receives voicemails for a called
+// number.
+func (m Mailboxes) ForNumber(called string) (Mailbox, bool) {
+	var catchAll *Mailbox
+	for i, mailbox := range m {
+		if len(mailbox.Numbers) == 0 {
+			catchAll = &m[i]
+		}
+		for _, number := range mailbox.Numbers {
+			if number == called {
+				return mailbox, true
+			}
+		}
+	}
+
+	if catchAll != nil {
+		return *catchAll, true
+	}
+	return Mailbox{}, false
+}
+
+func (m Mailboxes) numbers(include func(Mailbox) bool) []string {
+	numbers := []string{}
+	for _, mailbox := range m {
+		if include(mailbox) {
+			numbers = append(numbers, mailbox.Numbers...)
+		}
+	}
+	return numbers
+}
+
+// Filter returns the filter selecting the voicemails of a mailbox.
+func (m Mailboxes) Filter(mailbox Mailbox) Filter {
+	if len(mailbox.Numbers) > 0 {
+		return Filter{Numbers: mailbox.Numbers}
+	}
+	return Filter{ExcludeNumbers: m.numbers(func(Mailbox) bool { return true })}
+}
+
+// RetentionRules returns the rules enforcing the retention policies of
+// the mailboxes and the global policy for all other voicemails.
+func (m Mailboxes) RetentionRules(global RetentionPolicy) []RetentionRule {
+	rules := []RetentionRule{}
+	hasOwnPolicy := func(mailbox Mailbox) bool { return mailbox.Retention.Enabled() }
+	catchAllHasOwnPolicy := false
+
+	for _, mailbox := range m {
+		if hasOwnPolicy(mailbox) {
+			rules = append(rules, RetentionRule{
+				Name:   mailbox.Name,
+				Filter: m.Filter(mailbox),
+				Policy: mailbox.Retention,
+			})
+			if len(mailbox.Numbers) == 0 {
+				catchAllHasOwnPolicy = true
+			}
+		}
+	}
+
+	if !catchAllHasOwnPolicy {
+		excluded := m.numbers(hasOwnPolicy)
+		rules = append(rules, RetentionRule{
+			Filter: Filter{ExcludeNumbers: excluded},
+			Policy: global,
+		})
+	} else if included := m.numbers(func(mailbox Mailbox) bool {
+		return !hasOwnPolicy(mailbox)
+	}); len(included) > 0 {
+		rules = append(rules, RetentionRule{
+			Filter: Filter{Numbers: included},
+			Policy: global,
+		})
+	}
+
+	return rules
+}
@@ -2,18 +2,37 @@ package model
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"path"
-	"strconv"
+	"sort"
+	"strings"
 	"time"
 
 	_ "../external/sqlite"
+	"../metrics"
 	"../utils"
 )
 
 var logger = utils.Logger("model")
 
+var (
+	databaseErrors = metrics.NewCounter("voicemail_database_errors_total",
+		"Failed database operations by operation.", "operation")
+	audioBytes = metrics.NewCounter("voicemail_audio_stored_bytes_total",
+		"Bytes of MP3 audio stored.")
+)
+
+// countError counts a failed database operation.  Missing voicemails
+// are not counted.
+func countError(operation string, err error) error {
+	if err != nil && err != ErrNoVoicemail {
+		databaseErrors.Inc(operation)
+	}
+	return err
+}
+
 type Voicemail struct {
 	Id            int
 	Caller        string
@@ -21,11 +40,19 @@ type Voicemail struct {
 	Date          time.Time
 	Duration      time.Duration
 	VoicemailPath string
+	Archived      bool
+	Deleted       bool
+	Starred       bool
+	Read          bool
+	Notes         string
+	Transcript    string
+	Tags          []string
 }
 
 type Database struct {
 	channel    chan func(db *sql.DB)
 	storageDir string
+	events     *eventBus
 }
 
 func OpenDatabase(dbFile string, storageDir string) Database {
@@ -46,69 +73,407 @@ func OpenDatabase(dbFile string, storageDir string) Database {
                      duration INTEGER,
                      voicemail TEXT);`)
 
+		if err := migrate(db); err != nil {
+			logger.Panic(err)
+		}
+
 		for {
 			f := <-ch
 			f(db)
 		}
 	}()
-	return Database{channel: ch, storageDir: storageDir}
+	return Database{channel: ch, storageDir: storageDir, events: newEventBus()}
+}
+
+// Close closes the database after the queries sent before have
+// finished.  Queries sent afterwards fail.
+func (db Database) Close() error {
+	errorChannel := make(chan error)
+	db.channel <- func(conn *sql.DB) {
+		errorChannel <- conn.Close()
+	}
+	return <-errorChannel
+}
+
+// dateFormat is the format of the date column.
+const dateFormat = "2006-01-02 15:04:05.000-07:00"
+
+// Folder selects a subset of the stored voicemails.
+type Folder int
+
+const (
+	Inbox Folder = iota
+	Archive
+	Trash
+	// AllFolders selects the voicemails in all other folders.
+	AllFolders
+)
+
+var folderConditions = map[Folder]string{
+	Inbox:      "deleted = 0 AND archived = 0",
+	Archive:    "deleted = 0 AND archived = 1",
+	Trash:      "deleted = 1",
+	AllFolders: "1",
+}
+
+// ErrNoVoicemail is returned when an operation refers to a voicemail
+// id that is not stored in the database.
+var ErrNoVoicemail = errors.New("no such voicemail")
+
+const voicemailColumns = `id, caller, called, date, duration, voicemail,
+        archived, deleted, starred, read, notes, transcript,
+        (SELECT group_concat(name, ',') FROM tag WHERE tag.voicemail = voicemail.id)`
+
+// UnknownNumber replaces the caller or called number of voicemails
+// that were received without it.
+const UnknownNumber = "Unbekannt"
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanVoicemail(row scanner) (Voicemail, error) {
+	var voicemail Voicemail
+	var duration string
+	var date string
+	var tags sql.NullString
+	if err := row.Scan(
+		&voicemail.Id,
+		&voicemail.Caller,
+		&voicemail.Called,
+		&date,
+		&duration,
+		&voicemail.VoicemailPath,
+		&voicemail.Archived,
+		&voicemail.Deleted,
+		&voicemail.Starred,
+		&voicemail.Read,
+		&voicemail.Notes,
+		&voicemail.Transcript,
+		&tags); err != nil {
+
+		return Voicemail{}, err
+	}
+
+	voicemail.Tags = []string{}
+	if tags.Valid && tags.String != "" {
+		voicemail.Tags = strings.Split(tags.String, ",")
+		sort.Strings(voicemail.Tags)
+	}
+
+	if voicemail.Caller == "" {
+		voicemail.Caller = UnknownNumber
+	}
+	if voicemail.Called == "" {
+		voicemail.Called = UnknownNumber
+	}
+
+	var err error
+	voicemail.Duration, err = time.ParseDuration(duration + "s")
+	if err != nil {
+		return Voicemail{}, err
+	}
+	voicemail.Date, err = time.Parse(dateFormat, date)
+	if err != nil {
+		return Voicemail{}, err
+	}
+
+	return voicemail, nil
+}
+
+// migrations are applied in order to databases created by older
+// versions.  PRAGMA user_version records how many have been applied.
+var migrations = []string{
+	`ALTER TABLE voicemail ADD COLUMN archived INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE voicemail ADD COLUMN deleted INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE voicemail ADD COLUMN starred INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE voicemail ADD COLUMN read INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE voicemail ADD COLUMN notes TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE voicemail ADD COLUMN transcript TEXT NOT NULL DEFAULT ''`,
+	`CREATE TABLE tag (
+             voicemail INTEGER NOT NULL REFERENCES voicemail (id),
+             name TEXT NOT NULL,
+             PRIMARY KEY (voicemail, name))`,
+	`CREATE VIRTUAL TABLE voicemail_search USING fts4(caller, notes, transcript)`,
+	`INSERT INTO voicemail_search (docid, caller, notes, transcript)
+             SELECT id, caller, notes, transcript FROM voicemail`,
+	`CREATE TRIGGER voicemail_search_insert AFTER INSERT ON voicemail BEGIN
+             INSERT INTO voicemail_search (docid, caller, notes, transcript)
+             VALUES (new.id, new.caller, new.notes, new.transcript);
+         END`,
+	`CREATE TRIGGER voicemail_search_update AFTER UPDATE OF caller, notes, transcript ON voicemail BEGIN
+             UPDATE voicemail_search
+             SET caller = new.caller, notes = new.notes, transcript = new.transcript
+             WHERE docid = new.id;
+         END`,
+	`CREATE TRIGGER voicemail_search_delete AFTER DELETE ON voicemail BEGIN
+             DELETE FROM voicemail_search WHERE docid = old.id;
+             DELETE FROM tag WHERE voicemail = old.id;
+         END`,
+	`CREATE TABLE user (
+             id INTEGER PRIMARY KEY,
+             name TEXT NOT NULL UNIQUE,
+             password TEXT NOT NULL)`,
+	`CREATE TABLE session (
+             token TEXT PRIMARY KEY,
+             user INTEGER NOT NULL REFERENCES user (id),
+             csrf TEXT NOT NULL,
+             expires TEXT NOT NULL)`,
+	`CREATE TABLE user_mailbox (
+             user INTEGER NOT NULL REFERENCES user (id),
+             mailbox TEXT NOT NULL,
+             PRIMARY KEY (user, mailbox))`,
+	`INSERT INTO user_mailbox (user, mailbox) SELECT id, '*' FROM user`,
+	`CREATE TABLE api_token (
+             id INTEGER PRIMARY KEY,
+             user INTEGER NOT NULL REFERENCES user (id),
+             name TEXT NOT NULL,
+             token TEXT NOT NULL UNIQUE,
+             scopes TEXT NOT NULL,
+             created TEXT NOT NULL,
+             last_used TEXT)`,
+	`CREATE TABLE delivery (
+             id INTEGER PRIMARY KEY,
+             voicemail INTEGER NOT NULL,
+             channel TEXT NOT NULL,
+             target TEXT NOT NULL,
+             attempt INTEGER NOT NULL,
+             success INTEGER NOT NULL,
+             error TEXT NOT NULL DEFAULT '',
+             date TEXT NOT NULL)`,
+}
+
+func migrate(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+
+	for ; version < len(migrations); version++ {
+		if _, err := db.Exec(migrations[version]); err != nil {
+			return err
+		}
+		if _, err := db.Exec(fmt.Sprintf("PRAGMA user_version = %d", version+1)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetVoicemails returns the newest voicemails in a folder.  A limit
+// below 1 returns all of them.
+func (db Database) GetVoicemails(folder Folder, limit int) ([]Voicemail, error) {
+	page, err := db.GetPage(Query{Folder: folder, Limit: limit})
+	return page.Voicemails, err
 }
 
-func (db Database) GetVoicemails(limit int) ([]Voicemail, error) {
-	query := "SELECT * FROM voicemail ORDER BY date DESC LIMIT " + strconv.Itoa(limit)
+func (db Database) GetVoicemail(id int) (Voicemail, error) {
+	query := "SELECT " + voicemailColumns + " FROM voicemail WHERE id = ?"
 	errorChannel := make(chan error)
 
-	voicemails := []Voicemail{}
+	var voicemail Voicemail
 
 	db.channel <- func(db *sql.DB) {
-		s, err := db.Prepare(query)
+		var err error
+		voicemail, err = scanVoicemail(db.QueryRow(query, id))
+		if err == sql.ErrNoRows {
+			err = ErrNoVoicemail
+		}
+		errorChannel <- err
+	}
+
+	return voicemail, countError("get", <-errorChannel)
+}
+
+// GetVoicemailByPath returns the voicemail stored in an audio file.
+func (db Database) GetVoicemailByPath(voicemailPath string) (Voicemail, error) {
+	query := "SELECT " + voicemailColumns + " FROM voicemail WHERE voicemail = ?"
+	errorChannel := make(chan error)
+
+	var voicemail Voicemail
+
+	db.channel <- func(db *sql.DB) {
+		var err error
+		voicemail, err = scanVoicemail(db.QueryRow(query, voicemailPath))
+		if err == sql.ErrNoRows {
+			err = ErrNoVoicemail
+		}
+		errorChannel <- err
+	}
+
+	return voicemail, countError("get", <-errorChannel)
+}
+
+// update sets columns of a voicemail row and publishes the change.
+func (db Database) update(id int, set string, args ...interface{}) error {
+	errorChannel := make(chan error)
+	args = append(args, id)
+
+	db.channel <- func(conn *sql.DB) {
+		res, err := conn.Exec("UPDATE voicemail SET "+set+" WHERE id = ?", args...)
 		if err != nil {
 			errorChannel <- err
 			return
 		}
-		defer s.Close()
 
-		rows, err := s.Query()
-		for rows.Next() {
-			var voicemail Voicemail
-			var duration string
-			var date string
-			if err := rows.Scan(
-				&voicemail.Id,
-				&voicemail.Caller,
-				&voicemail.Called,
-				&date,
-				&duration,
-				&voicemail.VoicemailPath); err != nil {
+		n, err := res.RowsAffected()
+		if err == nil && n == 0 {
+			err = ErrNoVoicemail
+		}
+		if err == nil {
+			db.publishVoicemail(conn, VoicemailChanged, id)
+		}
+		errorChannel <- err
+	}
 
-				errorChannel <- err
-				return
-			}
+	return countError("update", <-errorChannel)
+}
 
-			if voicemail.Caller == "" {
-				voicemail.Caller = "Unbekannt"
-			}
-			if voicemail.Called == "" {
-				voicemail.Called = "Unbekannt"
-			}
+// DeleteVoicemail moves a voicemail into the trash.  It can be
+// restored with RestoreVoicemail until it is purged.  The change event
+// is marked as Trashed unless the voicemail was in the trash already.
+func (db Database) DeleteVoicemail(id int) error {
+	errorChannel := make(chan error)
+
+	db.channel <- func(conn *sql.DB) {
+		var deleted bool
+		err := conn.QueryRow("SELECT deleted FROM voicemail WHERE id = ?", id).Scan(&deleted)
+		if err == sql.ErrNoRows {
+			err = ErrNoVoicemail
+		}
+		if err == nil {
+			_, err = conn.Exec("UPDATE voicemail SET deleted = 1 WHERE id = ?", id)
+		}
+		if err == nil {
+			db.publishEvent(conn, Event{Type: VoicemailChanged, Trashed: !deleted}, id)
+		}
+		errorChannel <- err
+	}
+
+	return countError("update", <-errorChannel)
+}
+
+func (db Database) RestoreVoicemail(id int) error {
+	return db.update(id, "deleted = 0")
+}
+
+func (db Database) ArchiveVoicemail(id int) error {
+	return db.update(id, "archived = 1")
+}
+
+func (db Database) UnarchiveVoicemail(id int) error {
+	return db.update(id, "archived = 0")
+}
+
+// StarVoicemail marks a voicemail as important.  Starred voicemails
+// can be exempted from retention policies.
+func (db Database) StarVoicemail(id int) error {
+	return db.update(id, "starred = 1")
+}
+
+func (db Database) UnstarVoicemail(id int) error {
+	return db.update(id, "starred = 0")
+}
 
-			voicemail.Duration, err = time.ParseDuration(duration + "s")
-			if err != nil {
+func (db Database) MarkRead(id int) error {
+	return db.update(id, "read = 1")
+}
+
+func (db Database) MarkUnread(id int) error {
+	return db.update(id, "read = 0")
+}
+
+// PurgeVoicemail permanently removes a voicemail and its audio file.
+func (db Database) PurgeVoicemail(id int) error {
+	errorChannel := make(chan error)
+
+	db.channel <- func(conn *sql.DB) {
+		errorChannel <- db.purge(conn, id)
+	}
+
+	return countError("purge", <-errorChannel)
+}
+
+// EmptyTrash purges all voicemails in the trash that match the filter
+// and returns how many were removed.
+func (db Database) EmptyTrash(filter Filter) (int, error) {
+	errorChannel := make(chan error)
+	purged := 0
+	where, args := filter.conditions(Trash)
+
+	db.channel <- func(conn *sql.DB) {
+		rows, err := conn.Query("SELECT id FROM voicemail WHERE "+where, args...)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+
+		ids := []int{}
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
 				errorChannel <- err
 				return
 			}
-			voicemail.Date, err = time.Parse("2006-01-02 15:04:05.000-07:00", date)
-			if err != nil {
+			ids = append(ids, id)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+
+		for _, id := range ids {
+			if err := db.purge(conn, id); err != nil {
 				errorChannel <- err
 				return
 			}
+			purged++
+		}
+		errorChannel <- nil
+	}
+
+	err := countError("purge", <-errorChannel)
+	return purged, err
+}
 
-			voicemails = append(voicemails, voicemail)
+func (db Database) purge(conn *sql.DB, id int) error {
+	voicemail, err := scanVoicemail(conn.QueryRow(
+		"SELECT "+voicemailColumns+" FROM voicemail WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return ErrNoVoicemail
+	} else if err != nil {
+		return err
+	}
+	voicemailPath := voicemail.VoicemailPath
+
+	// The row is only deleted once the file is gone, so that a failed
+	// purge can be repeated.
+	tx, err := conn.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM voicemail WHERE id = ?", id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if voicemailPath != "" {
+		err = os.Remove(path.Join(db.storageDir, path.Base(voicemailPath)))
+		if err != nil && !os.IsNotExist(err) {
+			tx.Rollback()
+			return err
 		}
-		errorChannel <- err
 	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	logger.Info("Voicemail purged", "voicemail", id, "file", voicemailPath)
+	db.publish(Event{Type: VoicemailPurged, Voicemail: voicemail})
 
-	return voicemails, <-errorChannel
+	return nil
 }
 
 func createFile(dir string, filenameBase, filenameExt string) (string, *os.File, error) {
@@ -150,7 +515,7 @@ func (db Database) DumpRawMessage(voicemail Voicemail, rawMsg []byte) (string, e
 	filenameBase := time.Now().Format("20060102-150405")
 	filename, file, err := createFile(db.storageDir, filenameBase, ".b64")
 	if err == nil {
-		logger.Printf("Unprocessed voicemail from %v dumped: %v", voicemail.Caller, filename)
+		logger.Warn("Unprocessed voicemail dumped", "caller", voicemail.Caller, "file", filename)
 		file.Write(rawMsg)
 		file.Close()
 	}
@@ -164,13 +529,12 @@ func (db Database) AddVoicemail(voicemail Voicemail, voicemailAudio []byte) erro
 	db.channel <- func(conn *sql.DB) {
 		voicemailPath, err := saveVoicemailAudio(db.storageDir, voicemailAudio)
 		if err != nil {
-			logger.Print("Unable to save voicemail as MP3: ", err)
+			logger.Error("Unable to save voicemail as MP3", "error", err)
 			errorChannel <- err
 			return
 		}
 
 		voicemail.VoicemailPath = path.Base(voicemailPath)
-		logger.Print("Voicemail saved to ", voicemail.VoicemailPath)
 
 		ins, err := conn.Prepare(`INSERT INTO voicemail (
                                 caller, called, date, duration, voicemail
@@ -181,8 +545,8 @@ func (db Database) AddVoicemail(voicemail Voicemail, voicemailAudio []byte) erro
 		}
 		defer ins.Close()
 
-		date := voicemail.Date.Format("2006-01-02 15:04:05.000-07:00")
-		_, err = ins.Exec(voicemail.Caller,
+		date := voicemail.Date.Format(dateFormat)
+		res, err := ins.Exec(voicemail.Caller,
 			voicemail.Called,
 			date,
 			voicemail.Duration.Seconds(),
@@ -192,8 +556,96 @@ func (db Database) AddVoicemail(voicemail Voicemail, voicemailAudio []byte) erro
 			return
 		}
 
+		audioBytes.Add(float64(len(voicemailAudio)))
+		if id, err := res.LastInsertId(); err == nil {
+			logger.Info("Voicemail stored", "voicemail", id, "file", voicemail.VoicemailPath)
+			db.publishVoicemail(conn, VoicemailAdded, int(id))
+		}
 		errorChannel <- nil
 	}
 
-	return <-errorChannel
+	return countError("add", <-errorChannel)
+}
+
+// ImportVoicemail stores a voicemail copied from another database with
+// its folder, flags, notes, transcript and tags.  It returns the new
+// id.
+func (db Database) ImportVoicemail(voicemail Voicemail, voicemailAudio []byte) (int, error) {
+	for _, tag := range voicemail.Tags {
+		if tag == "" || strings.Contains(tag, ",") {
+			return 0, ErrInvalidTag
+		}
+	}
+
+	errorChannel := make(chan error)
+	var id int64
+
+	db.channel <- func(conn *sql.DB) {
+		voicemailPath, err := saveVoicemailAudio(db.storageDir, voicemailAudio)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+
+		tx, err := conn.Begin()
+		if err != nil {
+			os.Remove(voicemailPath)
+			errorChannel <- err
+			return
+		}
+		fail := func(err error) {
+			tx.Rollback()
+			os.Remove(voicemailPath)
+			errorChannel <- err
+		}
+
+		res, err := tx.Exec(`INSERT INTO voicemail (
+                                 caller, called, date, duration, voicemail,
+                                 archived, deleted, starred, read, notes, transcript
+                             ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			voicemail.Caller, voicemail.Called, voicemail.Date.Format(dateFormat),
+			voicemail.Duration.Seconds(), path.Base(voicemailPath),
+			voicemail.Archived, voicemail.Deleted, voicemail.Starred, voicemail.Read,
+			voicemail.Notes, voicemail.Transcript)
+		if err != nil {
+			fail(err)
+			return
+		}
+		if id, err = res.LastInsertId(); err != nil {
+			fail(err)
+			return
+		}
+		for _, tag := range voicemail.Tags {
+			if _, err := tx.Exec("INSERT OR IGNORE INTO tag (voicemail, name) VALUES (?, ?)", id, tag); err != nil {
+				fail(err)
+				return
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			fail(err)
+			return
+		}
+
+		audioBytes.Add(float64(len(voicemailAudio)))
+		logger.Info("Voicemail imported", "voicemail", id, "file", path.Base(voicemailPath))
+		db.publishVoicemail(conn, VoicemailAdded, int(id))
+		errorChannel <- nil
+	}
+
+	return int(id), countError("add", <-errorChannel)
+}
+
+// HasVoicemail reports whether a voicemail from the same caller to the
+// same number at the same time is stored already.
+func (db Database) HasVoicemail(voicemail Voicemail) (bool, error) {
+	errorChannel := make(chan error)
+	var exists bool
+
+	db.channel <- func(conn *sql.DB) {
+		errorChannel <- conn.QueryRow(`SELECT EXISTS (SELECT 1 FROM voicemail
+                                           WHERE caller = ? AND called = ? AND date = ?)`,
+			voicemail.Caller, voicemail.Called, voicemail.Date.Format(dateFormat)).Scan(&exists)
+	}
+
+	return exists, countError("get", <-errorChannel)
 }
@@ -0,0 +1,727 @@
+package model
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"math"
+	"os"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func openTestDatabase(t *testing.T) (Database, string) {
+	tempDir, err := ioutil.TempDir("", "voicemail")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	return OpenDatabase(path.Join(tempDir, "voicemail.sqlite"), tempDir), tempDir
+}
+
+func addTestVoicemail(t *testing.T, db Database, caller string, age time.Duration) Voicemail {
+	err := db.AddVoicemail(Voicemail{
+		Caller:   caller,
+		Called:   "12312234",
+		Date:     time.Now().Add(-age),
+		Duration: 3 * time.Second,
+	}, []byte("audio"))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	voicemails, err := db.GetVoicemails(Inbox, 1)
+	if err != nil || len(voicemails) != 1 {
+		t.Errorf("Unable to read back voicemail: %v", err)
+		t.FailNow()
+	}
+
+	return voicemails[0]
+}
+
+func callers(t *testing.T, db Database, folder Folder) []string {
+	voicemails, err := db.GetVoicemails(folder, -1)
+	if err != nil {
+		t.Error(err)
+	}
+
+	result := []string{}
+	for _, voicemail := range voicemails {
+		result = append(result, voicemail.Caller)
+	}
+	return result
+}
+
+func TestTrash(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	voicemail := addTestVoicemail(t, db, "5552341222", 0)
+
+	if err := db.DeleteVoicemail(voicemail.Id); err != nil {
+		t.Error(err)
+	}
+	if inbox, trash := callers(t, db, Inbox), callers(t, db, Trash); len(inbox) != 0 || len(trash) != 1 {
+		t.Errorf("Deleted voicemail not in trash: inbox %v, trash %v", inbox, trash)
+	}
+
+	if err := db.RestoreVoicemail(voicemail.Id); err != nil {
+		t.Error(err)
+	}
+	if inbox := callers(t, db, Inbox); len(inbox) != 1 {
+		t.Errorf("Restored voicemail not in inbox: %v", inbox)
+	}
+
+	if err := db.PurgeVoicemail(voicemail.Id); err != nil {
+		t.Error(err)
+	}
+	if _, err := os.Stat(path.Join(tempDir, voicemail.VoicemailPath)); !os.IsNotExist(err) {
+		t.Errorf("Audio file of purged voicemail still exists: %v", err)
+	}
+	if err := db.PurgeVoicemail(voicemail.Id); err != ErrNoVoicemail {
+		t.Errorf("Expected %v, got %v", ErrNoVoicemail, err)
+	}
+}
+
+func TestPurgeFailure(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	voicemail := addTestVoicemail(t, db, "5552341222", 0)
+
+	// A non-empty directory cannot be removed, not even by root.
+	file := path.Join(tempDir, voicemail.VoicemailPath)
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(path.Join(file, "blocked"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.PurgeVoicemail(voicemail.Id); err == nil {
+		t.Error("Purge succeeded without removing the file")
+	}
+	if _, err := db.GetVoicemail(voicemail.Id); err != nil {
+		t.Errorf("Voicemail removed although its file was kept: %v", err)
+	}
+
+	if err := os.RemoveAll(file); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.PurgeVoicemail(voicemail.Id); err != nil {
+		t.Errorf("Purge not repeated: %v", err)
+	}
+}
+
+func TestArchive(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	voicemail := addTestVoicemail(t, db, "5552341222", 0)
+
+	if err := db.ArchiveVoicemail(voicemail.Id); err != nil {
+		t.Error(err)
+	}
+	if inbox, archive := callers(t, db, Inbox), callers(t, db, Archive); len(inbox) != 0 || len(archive) != 1 {
+		t.Errorf("Archived voicemail not in archive: inbox %v, archive %v", inbox, archive)
+	}
+
+	if err := db.UnarchiveVoicemail(voicemail.Id); err != nil {
+		t.Error(err)
+	}
+	if inbox := callers(t, db, Inbox); len(inbox) != 1 {
+		t.Errorf("Unarchived voicemail not in inbox: %v", inbox)
+	}
+
+	if err := db.ArchiveVoicemail(0); err != ErrNoVoicemail {
+		t.Errorf("Expected %v, got %v", ErrNoVoicemail, err)
+	}
+}
+
+func TestEmptyTrash(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	deleted := addTestVoicemail(t, db, "deleted", 0)
+	addTestVoicemail(t, db, "kept", 0)
+	if err := db.DeleteVoicemail(deleted.Id); err != nil {
+		t.Error(err)
+	}
+
+	purged, err := db.EmptyTrash(Filter{})
+	if err != nil {
+		t.Error(err)
+	}
+	if inbox, trash := callers(t, db, Inbox), callers(t, db, Trash); purged != 1 || len(inbox) != 1 || len(trash) != 0 {
+		t.Errorf("Expected trash to be emptied: purged %d, inbox %v, trash %v", purged, inbox, trash)
+	}
+}
+
+func TestRetention(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	addTestVoicemail(t, db, "oldest", 72*time.Hour)
+	starred := addTestVoicemail(t, db, "starred", 48*time.Hour)
+	addTestVoicemail(t, db, "older", 24*time.Hour)
+	addTestVoicemail(t, db, "newest", 0)
+
+	if err := db.StarVoicemail(starred.Id); err != nil {
+		t.Error(err)
+	}
+
+	purged, err := db.EnforceRetention(RetentionRule{Policy: RetentionPolicy{MaxCount: 2, KeepStarred: true}})
+	if err != nil {
+		t.Error(err)
+	}
+	if kept := callers(t, db, Inbox); purged != 1 || len(kept) != 3 || kept[2] != "starred" {
+		t.Errorf("Expected oldest voicemail to be purged, kept %v", kept)
+	}
+
+	purged, err = db.EnforceRetention(RetentionRule{Policy: RetentionPolicy{MaxAge: 36 * time.Hour}})
+	if err != nil {
+		t.Error(err)
+	}
+	if kept := callers(t, db, Inbox); purged != 1 || len(kept) != 2 {
+		t.Errorf("Expected starred voicemail to be purged, kept %v", kept)
+	}
+
+	purged, err = db.EnforceRetention(RetentionRule{Policy: RetentionPolicy{MaxSize: int64(len("audio"))}})
+	if err != nil {
+		t.Error(err)
+	}
+	if kept := callers(t, db, Inbox); purged != 1 || len(kept) != 1 || kept[0] != "newest" {
+		t.Errorf("Expected only newest voicemail to be kept, kept %v", kept)
+	}
+}
+
+func TestPagination(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	for _, caller := range []string{"5", "4", "3", "2", "1"} {
+		age, _ := time.ParseDuration(caller + "h")
+		addTestVoicemail(t, db, caller, age)
+	}
+
+	pageCallers := func(page Page) string {
+		result := ""
+		for _, voicemail := range page.Voicemails {
+			result += voicemail.Caller
+		}
+		return result
+	}
+
+	first, err := db.GetPage(Query{Limit: 2})
+	if err != nil || pageCallers(first) != "12" || first.Newer != "" || first.Older == "" {
+		t.Errorf("Unexpected first page %v: %v", first, err)
+	}
+
+	second, err := db.GetPage(Query{Before: first.Older, Limit: 2})
+	if err != nil || pageCallers(second) != "34" || second.Newer == "" || second.Older == "" {
+		t.Errorf("Unexpected second page %v: %v", second, err)
+	}
+
+	last, err := db.GetPage(Query{Before: second.Older, Limit: 2})
+	if err != nil || pageCallers(last) != "5" || last.Older != "" {
+		t.Errorf("Unexpected last page %v: %v", last, err)
+	}
+
+	back, err := db.GetPage(Query{After: second.Newer, Limit: 2})
+	if err != nil || pageCallers(back) != "12" || back.Newer != "" {
+		t.Errorf("Unexpected page going back %v: %v", back, err)
+	}
+
+	if _, err := db.GetPage(Query{Before: "garbage"}); err != ErrInvalidCursor {
+		t.Errorf("Expected %v, got %v", ErrInvalidCursor, err)
+	}
+}
+
+func TestPaginationTimeZones(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	// The dates sort differently as text.
+	date := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, zone := range []*time.Location{time.FixedZone("", 5*3600), time.UTC, time.FixedZone("", 3*3600)} {
+		err := db.AddVoicemail(Voicemail{
+			Caller: strconv.Itoa(i + 1),
+			Called: "12312234",
+			Date:   date.Add(-time.Duration(i) * time.Hour).In(zone),
+		}, []byte("audio"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found := ""
+	var cursor Cursor
+	for {
+		page, err := db.GetPage(Query{Before: cursor, Limit: 1})
+		if err != nil || len(page.Voicemails) != 1 {
+			t.Fatalf("Unexpected page %v: %v", page, err)
+		}
+		found += page.Voicemails[0].Caller
+		if cursor = page.Older; cursor == "" {
+			break
+		}
+	}
+	if found != "123" {
+		t.Errorf("Expected pages 123, got %s", found)
+	}
+
+	page, err := db.GetPage(Query{Filter: Filter{From: date.Add(-90 * time.Minute)}})
+	if err != nil || len(page.Voicemails) != 2 || page.Voicemails[1].Caller != "2" {
+		t.Errorf("Unexpected filtered page %v: %v", page, err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	old := addTestVoicemail(t, db, "Erika Mustermann", 24*time.Hour)
+	recent := addTestVoicemail(t, db, "5552341222", time.Hour)
+
+	if err := db.SetNotes(old.Id, "Rückruf wegen Rechnung"); err != nil {
+		t.Error(err)
+	}
+	if err := db.SetTags(recent.Id, []string{"work", "urgent"}); err != nil {
+		t.Error(err)
+	}
+	if err := db.MarkRead(old.Id); err != nil {
+		t.Error(err)
+	}
+
+	tests := []struct {
+		filter   Filter
+		expected string
+	}{
+		{Filter{Text: MatchWords("mustermann")}, "Erika Mustermann"},
+		{Filter{Text: MatchWords("Rech")}, "Erika Mustermann"},
+		{Filter{Caller: "5552"}, "5552341222"},
+		{Filter{Caller: "5_5"}, ""},
+		{Filter{Caller: "%"}, ""},
+		{Filter{Called: `\`}, ""},
+		{Filter{From: time.Now().Add(-2 * time.Hour)}, "5552341222"},
+		{Filter{Until: time.Now().Add(-2 * time.Hour)}, "Erika Mustermann"},
+		{Filter{Read: OnlyUnread}, "5552341222"},
+		{Filter{Read: OnlyRead}, "Erika Mustermann"},
+		{Filter{Tags: []string{"urgent"}}, "5552341222"},
+		{Filter{Tags: []string{"urgent", "private"}}, ""},
+		{Filter{MaxDuration: 2 * time.Second}, ""},
+	}
+
+	for _, test := range tests {
+		page, err := db.GetPage(Query{Filter: test.filter})
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		found := ""
+		for _, voicemail := range page.Voicemails {
+			found += voicemail.Caller
+		}
+		if found != test.expected {
+			t.Errorf("Filter %+v: expected %q, got %q", test.filter, test.expected, found)
+		}
+
+		if n, err := db.Count(Inbox, test.filter); err != nil || n != len(page.Voicemails) {
+			t.Errorf("Filter %+v: expected count %d, got %d, %v", test.filter, len(page.Voicemails), n, err)
+		}
+	}
+
+	voicemail, err := db.GetVoicemail(recent.Id)
+	if err != nil || len(voicemail.Tags) != 2 || voicemail.Tags[0] != "urgent" {
+		t.Errorf("Tags not stored: %v, %v", voicemail.Tags, err)
+	}
+}
+
+func TestMailboxes(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	mailboxes := Mailboxes{
+		{Name: "home", Numbers: []string{"111"}},
+		{Name: "office", Numbers: []string{"222", "333"},
+			Retention: RetentionPolicy{MaxCount: 1}},
+		{Name: "other"},
+	}
+	if err := mailboxes.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	for _, called := range []string{"111", "222", "333", "444"} {
+		err := db.AddVoicemail(Voicemail{
+			Caller: "caller-" + called,
+			Called: called,
+			Date:   time.Now(),
+		}, []byte("audio"))
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	for name, expected := range map[string]int{"home": 1, "office": 2, "other": 1} {
+		mailbox, _ := mailboxes.Get(name)
+		page, err := db.GetPage(Query{Filter: mailboxes.Filter(mailbox)})
+		if err != nil || len(page.Voicemails) != expected {
+			t.Errorf("Mailbox %s: expected %d voicemails, got %v (%v)", name, expected, page.Voicemails, err)
+		}
+	}
+
+	if mailbox, _ := mailboxes.ForNumber("444"); mailbox.Name != "other" {
+		t.Errorf("Expected 444 to be in mailbox other, got %q", mailbox.Name)
+	}
+
+	rules := mailboxes.RetentionRules(RetentionPolicy{MaxCount: 10})
+	if len(rules) != 2 || rules[0].Name != "office" {
+		t.Errorf("Unexpected retention rules %+v", rules)
+	}
+	for _, rule := range rules {
+		if _, err := db.EnforceRetention(rule); err != nil {
+			t.Error(err)
+		}
+	}
+	if all := callers(t, db, Inbox); len(all) != 3 {
+		t.Errorf("Expected one office voicemail to be purged, kept %v", all)
+	}
+
+	invalid := Mailboxes{{Name: "a", Numbers: []string{"1"}}, {Name: "b", Numbers: []string{"1"}}}
+	if err := invalid.Validate(); err == nil {
+		t.Error("Duplicate number not detected")
+	}
+	for _, name := range []string{AllMailboxes, "a/b", "a+", "#", "a b", "a,b", "Büro"} {
+		if err := (Mailboxes{{Name: name}}).Validate(); err == nil {
+			t.Errorf("Invalid name %q accepted", name)
+		}
+	}
+
+	parsed, err := ParseMailboxes([]byte(`[{"name": "home", "retention": {"max_size": 2}}]`))
+	if err != nil || parsed[0].Retention.MaxSize != 2*1024*1024 {
+		t.Errorf("Unexpected retention %+v, %v", parsed, err)
+	}
+}
+
+func TestUsers(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	if err := db.CreateUser("alice", "short", []string{AllMailboxes}); err != ErrPasswordTooShort {
+		t.Errorf("Expected %v, got %v", ErrPasswordTooShort, err)
+	}
+	if err := db.CreateUser("alice", "correct horse", []string{AllMailboxes}); err != nil {
+		t.Error(err)
+	}
+	if err := db.CreateUser("alice", "correct horse", []string{AllMailboxes}); err != ErrUserExists {
+		t.Errorf("Expected %v, got %v", ErrUserExists, err)
+	}
+
+	if _, err := db.Authenticate("alice", "wrong password"); err != ErrInvalidCredentials {
+		t.Errorf("Expected %v, got %v", ErrInvalidCredentials, err)
+	}
+	user, err := db.Authenticate("alice", "correct horse")
+	if err != nil || user.Name != "alice" {
+		t.Errorf("Authentication failed: %v", err)
+	}
+
+	session, err := db.CreateSession(user, time.Hour)
+	if err != nil {
+		t.Error(err)
+	}
+	if found, err := db.GetSession(session.Token); err != nil || found.User.Name != user.Name || found.CSRFToken != session.CSRFToken {
+		t.Errorf("Session not found: %v, %v", found, err)
+	}
+
+	if err := db.SetPassword("alice", "battery staple"); err != nil {
+		t.Error(err)
+	}
+	if _, err := db.GetSession(session.Token); err != ErrNoSession {
+		t.Errorf("Session survived password change: %v", err)
+	}
+
+	expired, _ := db.CreateSession(user, -time.Minute)
+	if _, err := db.GetSession(expired.Token); err != ErrNoSession {
+		t.Errorf("Expired session accepted: %v", err)
+	}
+}
+
+func TestPermissions(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	mailboxes := Mailboxes{
+		{Name: "home", Numbers: []string{"111"}},
+		{Name: "office", Numbers: []string{"222"}},
+		{Name: "other"},
+	}
+
+	for _, called := range []string{"111", "222", "333"} {
+		err := db.AddVoicemail(Voicemail{Caller: called, Called: called, Date: time.Now()}, []byte("audio"))
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	tests := []struct {
+		grants   []string
+		expected string
+	}{
+		{[]string{AllMailboxes}, "111222333"},
+		{[]string{"home"}, "111"},
+		{[]string{"other"}, "333"},
+		{[]string{"office", "other"}, "222333"},
+		{[]string{"222"}, "222"},
+		{[]string{}, ""},
+	}
+
+	for _, test := range tests {
+		p := mailboxes.Permissions(test.grants)
+		page, err := db.GetPage(Query{Filter: Filter{Permissions: &p}})
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		found, allowed := "", ""
+		for i := len(page.Voicemails) - 1; i >= 0; i-- {
+			found += page.Voicemails[i].Caller
+		}
+		for _, called := range []string{"111", "222", "333"} {
+			if p.Allows(called) {
+				allowed += called
+			}
+		}
+		if found != test.expected || allowed != test.expected {
+			t.Errorf("Grants %v: expected %q, found %q, allowed %q", test.grants, test.expected, found, allowed)
+		}
+	}
+}
+
+func TestTokens(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	if err := db.CreateUser("alice", "correct horse", []string{"home"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := db.CreateToken("alice", "dashboard", []Scope{"root"}); err != ErrInvalidScope {
+		t.Errorf("Expected %v, got %v", ErrInvalidScope, err)
+	}
+	if _, _, err := db.CreateToken("bob", "dashboard", []Scope{ScopeRead}); err != ErrNoUser {
+		t.Errorf("Expected %v, got %v", ErrNoUser, err)
+	}
+
+	token, secret, err := db.CreateToken("alice", "dashboard", []Scope{ScopeWrite})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session, err := db.AuthenticateToken(secret)
+	if err != nil || session.User.Name != "alice" || strings.Join(session.User.Mailboxes, ",") != "home" {
+		t.Errorf("Token not accepted: %v, %v", session, err)
+	}
+	if !session.HasScope(ScopeRead) || !session.HasScope(ScopeWrite) || session.HasScope(ScopeAdmin) ||
+		session.HasScope(ScopeFeed) {
+		t.Errorf("Unexpected scopes: %v", session.Scopes)
+	}
+
+	if _, _, err := db.CreateToken("alice", "podcast", []Scope{ScopeFeed, ScopeRead}); err != ErrInvalidScope {
+		t.Errorf("Expected %v, got %v", ErrInvalidScope, err)
+	}
+	feedToken, feedSecret, err := db.CreateToken("alice", "podcast", []Scope{ScopeFeed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err = db.AuthenticateToken(feedSecret)
+	if err != nil || !session.HasScope(ScopeFeed) || session.HasScope(ScopeRead) {
+		t.Errorf("Unexpected feed token session: %v, %v", session, err)
+	}
+	if err := db.RevokeToken(feedToken.Id, "alice"); err != nil {
+		t.Error(err)
+	}
+	if _, err := db.AuthenticateToken(secret + "x"); err != ErrNoToken {
+		t.Errorf("Expected %v, got %v", ErrNoToken, err)
+	}
+
+	tokens, err := db.GetTokens("alice")
+	if err != nil || len(tokens) != 1 || tokens[0].Name != "dashboard" || tokens[0].LastUsed.IsZero() {
+		t.Errorf("Unexpected tokens: %v, %v", tokens, err)
+	}
+
+	if err := db.RevokeToken(token.Id, "bob"); err != ErrNoToken {
+		t.Errorf("Revoked token of another user: %v", err)
+	}
+	if err := db.RevokeToken(token.Id, "alice"); err != nil {
+		t.Error(err)
+	}
+	if _, err := db.AuthenticateToken(secret); err != ErrNoToken {
+		t.Errorf("Revoked token accepted: %v", err)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	events, unsubscribe := db.Subscribe()
+	defer unsubscribe()
+
+	voicemail := addTestVoicemail(t, db, "0301234567", time.Minute)
+	if err := db.MarkRead(voicemail.Id); err != nil {
+		t.Error(err)
+	}
+	if err := db.SetTags(voicemail.Id, []string{"Rückruf"}); err != nil {
+		t.Error(err)
+	}
+	if err := db.PurgeVoicemail(voicemail.Id); err != nil {
+		t.Error(err)
+	}
+
+	expected := []Event{
+		{Type: VoicemailAdded, Voicemail: Voicemail{Id: voicemail.Id}},
+		{Type: VoicemailChanged, Voicemail: Voicemail{Id: voicemail.Id, Read: true}},
+		{Type: VoicemailChanged, Voicemail: Voicemail{Id: voicemail.Id, Read: true, Tags: []string{"Rückruf"}}},
+		{Type: VoicemailPurged, Voicemail: Voicemail{Id: voicemail.Id, Read: true, Tags: []string{"Rückruf"}}},
+	}
+	for _, e := range expected {
+		select {
+		case event := <-events:
+			if event.Type != e.Type || event.Voicemail.Id != e.Voicemail.Id ||
+				event.Voicemail.Read != e.Voicemail.Read ||
+				len(event.Voicemail.Tags) != len(e.Voicemail.Tags) ||
+				event.Voicemail.Caller != "0301234567" {
+				t.Errorf("Expected %v, got %v", e, event)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("No %s event", e.Type)
+		}
+	}
+
+	unsubscribe()
+	if _, ok := <-events; ok {
+		t.Error("Channel not closed after unsubscribing")
+	}
+	addTestVoicemail(t, db, "0301234567", time.Minute)
+
+	// Held events go to the subscribers at the time they are released.
+	old, unsubscribeOld := db.Subscribe()
+	release := db.HoldEvents()
+	voicemail = addTestVoicemail(t, db, "0301234567", time.Minute)
+	unsubscribeOld()
+	events, unsubscribe = db.Subscribe()
+	defer unsubscribe()
+	release()
+	if event, ok := <-old; ok {
+		t.Errorf("Unexpected event %v for the old subscriber", event)
+	}
+	select {
+	case event := <-events:
+		if event.Type != VoicemailAdded || event.Voicemail.Id != voicemail.Id {
+			t.Errorf("Expected added event for %d, got %v", voicemail.Id, event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Held event not released")
+	}
+}
+
+func TestClose(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	addTestVoicemail(t, db, "caller", 0)
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.GetVoicemails(Inbox, 0); err == nil {
+		t.Error("Database still usable after closing")
+	}
+}
+
+func TestHealth(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	if err := db.Ping(time.Second); err != nil {
+		t.Error(err)
+	}
+	if err := db.CheckStorage(0); err != nil {
+		t.Error(err)
+	}
+	if err := db.CheckStorage(math.MaxUint64); err == nil {
+		t.Error("Free space not checked")
+	}
+	files, _ := ioutil.ReadDir(tempDir)
+	if len(files) != 1 {
+		t.Errorf("Unexpected files left: %v", files)
+	}
+
+	missing := Database{channel: db.channel, storageDir: path.Join(tempDir, "missing")}
+	if err := missing.CheckStorage(0); err == nil {
+		t.Error("Missing storage directory not reported")
+	}
+
+	done := make(chan bool)
+	defer close(done)
+	db.channel <- func(conn *sql.DB) {
+		<-done
+	}
+	if err := db.Ping(10 * time.Millisecond); err != ErrTimeout {
+		t.Errorf("Blocked database not reported: %v", err)
+	}
+}
+
+func TestImportVoicemail(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	voicemail := Voicemail{
+		Caller:     "030123456",
+		Called:     "12312234",
+		Date:       time.Date(2024, 3, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600)),
+		Duration:   7 * time.Second,
+		Archived:   true,
+		Starred:    true,
+		Read:       true,
+		Notes:      "Rückruf",
+		Transcript: "Hallo",
+		Tags:       []string{"arbeit", "dringend"},
+	}
+	if exists, err := db.HasVoicemail(voicemail); err != nil || exists {
+		t.Fatalf("Unexpected duplicate: %v", err)
+	}
+	id, err := db.ImportVoicemail(voicemail, []byte("audio"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	imported, err := db.GetVoicemail(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	voicemail.Id, voicemail.VoicemailPath = id, imported.VoicemailPath
+	if !imported.Date.Equal(voicemail.Date) {
+		t.Errorf("Unexpected date %v", imported.Date)
+	}
+	imported.Date = voicemail.Date
+	if !reflect.DeepEqual(imported, voicemail) {
+		t.Errorf("Expected %+v, got %+v", voicemail, imported)
+	}
+	if data, err := ioutil.ReadFile(path.Join(tempDir, imported.VoicemailPath)); err != nil || string(data) != "audio" {
+		t.Errorf("Unexpected audio %q: %v", data, err)
+	}
+	if exists, err := db.HasVoicemail(voicemail); err != nil || !exists {
+		t.Errorf("Duplicate not found: %v", err)
+	}
+
+	voicemail.Tags = []string{"a,b"}
+	if _, err := db.ImportVoicemail(voicemail, []byte("audio")); err != ErrInvalidTag {
+		t.Errorf("Invalid tag accepted: %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Cursor marks a position in the list of voicemails, which is ordered
+// by date and id.  Cursors are opaque and safe to use in URLs.  The
+// empty cursor marks the start of the list.
+type Cursor string
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+func cursorOf(voicemail Voicemail) Cursor {
+	position := voicemail.Date.Format(dateFormat) + "|" + strconv.Itoa(voicemail.Id)
+	return Cursor(base64.RawURLEncoding.EncodeToString([]byte(position)))
+}
+
+func (c Cursor) decode() (date string, id int, err error) {
+	position, err := base64.RawURLEncoding.DecodeString(string(c))
+	if err != nil {
+		return "", 0, ErrInvalidCursor
+	}
+
+	i := strings.LastIndex(string(position), "|")
+	if i < 0 {
+		return "", 0, ErrInvalidCursor
+	}
+
+	id, err = strconv.Atoi(string(position[i+1:]))
+	if err != nil {
+		return "", 0, ErrInvalidCursor
+	}
+
+	return string(position[:i]), id, nil
+}
+
+// Query selects a page of voicemails.  At most one of Before and
+// After may be set.
+type Query struct {
+	Folder Folder
+	Filter Filter
+	// Before selects voicemails older than the cursor.
+	Before Cursor
+	// After selects voicemails newer than the cursor.
+	After Cursor
+	// Limit is the maximum page size.  A limit below 1 selects all
+	// remaining voicemails.
+	Limit int
+}
+
+// Page is a list of voicemails ordered from newest to oldest.  Older
+// and Newer are the cursors for the adjacent pages and are empty if
+// there are no more voicemails in that direction.
+type Page struct {
+	Voicemails []Voicemail
+	Older      Cursor
+	Newer      Cursor
+}
+
+// Dates are compared with julianday() like in the filters, as they are
+// stored with the time zone offset of the voicemail.
+const (
+	olderCondition = "(julianday(date) < julianday(?) OR (julianday(date) = julianday(?) AND id < ?))"
+	newerCondition = "(julianday(date) > julianday(?) OR (julianday(date) = julianday(?) AND id > ?))"
+)
+
+func (db Database) GetPage(q Query) (Page, error) {
+	filter, filterArgs := q.Filter.conditions(q.Folder)
+	where := filter
+	args := append([]interface{}{}, filterArgs...)
+	order := "julianday(date) DESC, id DESC"
+
+	if q.Before != "" && q.After != "" {
+		return Page{}, errors.New("only one of Before and After may be set")
+	} else if q.Before != "" {
+		date, id, err := q.Before.decode()
+		if err != nil {
+			return Page{}, err
+		}
+		where += " AND " + olderCondition
+		args = append(args, date, date, id)
+	} else if q.After != "" {
+		date, id, err := q.After.decode()
+		if err != nil {
+			return Page{}, err
+		}
+		where += " AND " + newerCondition
+		args = append(args, date, date, id)
+		order = "julianday(date) ASC, id ASC"
+	}
+
+	limit := q.Limit
+	if limit < 1 {
+		limit = -1
+	}
+	args = append(args, limit)
+
+	query := "SELECT " + voicemailColumns + " FROM voicemail WHERE " +
+		where + " ORDER BY " + order + " LIMIT ?"
+	errorChannel := make(chan error)
+
+	page := Page{Voicemails: []Voicemail{}}
+
+	db.channel <- func(conn *sql.DB) {
+		rows, err := conn.Query(query, args...)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			voicemail, err := scanVoicemail(rows)
+			if err != nil {
+				errorChannel <- err
+				return
+			}
+
+			page.Voicemails = append(page.Voicemails, voicemail)
+		}
+		if err := rows.Err(); err != nil {
+			errorChannel <- err
+			return
+		}
+
+		if q.After != "" {
+			for i, j := 0, len(page.Voicemails)-1; i < j; i, j = i+1, j-1 {
+				page.Voicemails[i], page.Voicemails[j] = page.Voicemails[j], page.Voicemails[i]
+			}
+		}
+
+		if len(page.Voicemails) == 0 {
+			errorChannel <- nil
+			return
+		}
+
+		first := page.Voicemails[0]
+		last := page.Voicemails[len(page.Voicemails)-1]
+		page.Newer, err = adjacent(conn, filter, filterArgs, newerCondition, first)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+		page.Older, err = adjacent(conn, filter, filterArgs, olderCondition, last)
+		errorChannel <- err
+	}
+
+	return page, countError("query", <-errorChannel)
+}
+
+// adjacent returns the cursor of voicemail if there are more
+// voicemails matching the filter in the direction given by condition.
+func adjacent(conn *sql.DB, filter string, filterArgs []interface{}, condition string, voicemail Voicemail) (Cursor, error) {
+	date := voicemail.Date.Format(dateFormat)
+	args := append(append([]interface{}{}, filterArgs...), date, date, voicemail.Id)
+
+	var exists bool
+	err := conn.QueryRow("SELECT EXISTS (SELECT 1 FROM voicemail WHERE "+
+		filter+" AND "+condition+")", args...).Scan(&exists)
+	if err != nil || !exists {
+		return "", err
+	}
+
+	return cursorOf(voicemail), nil
+}
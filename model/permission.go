@@ -0,0 +1,112 @@
+package model
+
+import (
+	"strings"
+)
+
+// AllMailboxes grants a user access to all voicemails.
+const AllMailboxes = "*"
+
+// Permissions describe the voicemails a user may access.
+type Permissions struct {
+	// All grants access to every voicemail.
+	All bool
+	// Numbers are the called numbers the user may access.
+	Numbers []string
+	// Unassigned grants access to the voicemails for numbers that are
+	// not in Assigned, i.e. to the catch-all mailbox.
+	Unassigned bool
+	Assigned   []string
+}
+
+// Permissions resolves the mailboxes granted to a user.  A grant is
+// AllMailboxes, the name of a mailbox or a called number.
+func (m Mailboxes) Permissions(grants []string) Permissions {
+	var p Permissions
+	for _, grant := range grants {
+		if grant == AllMailboxes {
+			return Permissions{All: true}
+		}
+
+		mailbox, ok := m.Get(grant)
+		if !ok {
+			p.Numbers = append(p.Numbers, grant)
+		} else if len(mailbox.Numbers) > 0 {
+			p.Numbers = append(p.Numbers, mailbox.Numbers...)
+		} else {
+			p.Unassigned = true
+			p.Assigned = m.numbers(func(Mailbox) bool { return true })
+		}
+	}
+	return p
+}
+
+// Allows reports whether a voicemail for the called number may be
+// accessed.
+func (p Permissions) Allows(called string) bool {
+	if p.All {
+		return true
+	}
+
+	for _, number := range p.Numbers {
+		if number == called {
+			return true
+		}
+	}
+
+	if p.Unassigned {
+		for _, number := range p.Assigned {
+			if number == called {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// AllowsMailbox reports whether all voicemails of a mailbox may be
+// accessed.
+func (m Mailboxes) AllowsMailbox(p Permissions, mailbox Mailbox) bool {
+	if p.All {
+		return true
+	}
+	if len(mailbox.Numbers) == 0 {
+		return p.Unassigned
+	}
+	for _, number := range mailbox.Numbers {
+		if !p.Allows(number) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p Permissions) conditions() (string, []interface{}) {
+	if p.All {
+		return "1", nil
+	}
+
+	conditions := []string{}
+	args := []interface{}{}
+	if len(p.Numbers) > 0 {
+		conditions = append(conditions, "called IN ("+placeholders(len(p.Numbers))+")")
+		for _, number := range p.Numbers {
+			args = append(args, number)
+		}
+	}
+	if p.Unassigned && len(p.Assigned) > 0 {
+		conditions = append(conditions, "called NOT IN ("+placeholders(len(p.Assigned))+")")
+		for _, number := range p.Assigned {
+			args = append(args, number)
+		}
+	} else if p.Unassigned {
+		conditions = append(conditions, "1")
+	}
+
+	if len(conditions) == 0 {
+		return "0", nil
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
@@ -0,0 +1,212 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"../utils"
+)
+
+// RetentionPolicy describes which voicemails are purged automatically.
+// A zero MaxAge, MaxCount or MaxSize disables the respective rule.
+type RetentionPolicy struct {
+	// MaxAge is the age after which a voicemail is purged.
+	MaxAge time.Duration
+	// MaxCount is the number of voicemails that are kept.
+	MaxCount int
+	// MaxSize is the total size in bytes of the audio files that are
+	// kept.
+	MaxSize int64
+	// KeepStarred exempts starred voicemails from all rules.  They do
+	// not count towards MaxCount and MaxSize either.
+	KeepStarred bool
+}
+
+func (p RetentionPolicy) Enabled() bool {
+	return p.MaxAge > 0 || p.MaxCount > 0 || p.MaxSize > 0
+}
+
+// UnmarshalJSON reads a policy written as
+//
+//	{"max_age": "8760h", "max_count": 100, "max_size": 500, "keep_starred": true}
+//
+// max_size is given in megabytes like -retention-max-size.  Omitted
+// rules are disabled, keep_starred defaults to true.
+func (p *RetentionPolicy) UnmarshalJSON(data []byte) error {
+	var v struct {
+		MaxAge      string `json:"max_age"`
+		MaxCount    int    `json:"max_count"`
+		MaxSize     int64  `json:"max_size"`
+		KeepStarred *bool  `json:"keep_starred"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*p = RetentionPolicy{
+		MaxCount:    v.MaxCount,
+		MaxSize:     v.MaxSize * 1024 * 1024,
+		KeepStarred: v.KeepStarred == nil || *v.KeepStarred,
+	}
+	if v.MaxAge != "" {
+		var err error
+		if p.MaxAge, err = time.ParseDuration(v.MaxAge); err != nil {
+			return fmt.Errorf("max_age: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// RetentionRule applies a policy to the voicemails matching a filter.
+type RetentionRule struct {
+	// Name identifies the rule in the log.
+	Name   string
+	Filter Filter
+	Policy RetentionPolicy
+}
+
+type retentionCandidate struct {
+	id      int
+	caller  string
+	date    time.Time
+	size    int64
+	starred bool
+}
+
+// EnforceRetention purges all voicemails matching the rule's filter,
+// including archived ones and those in the trash, that violate the
+// rule's policy.  The newest voicemails are kept first.  It returns the
+// number of purged voicemails.
+func (db Database) EnforceRetention(rule RetentionRule) (int, error) {
+	policy := rule.Policy
+	if !policy.Enabled() {
+		return 0, nil
+	}
+
+	errorChannel := make(chan error)
+	purged := 0
+
+	db.channel <- func(conn *sql.DB) {
+		candidates, err := db.retentionCandidates(conn, rule.Filter)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+
+		now := time.Now()
+		var count int
+		var size int64
+		for _, c := range candidates {
+			if c.starred && policy.KeepStarred {
+				continue
+			}
+
+			reason := ""
+			switch {
+			case policy.MaxAge > 0 && now.Sub(c.date) > policy.MaxAge:
+				reason = "older than " + policy.MaxAge.String()
+			case policy.MaxCount > 0 && count >= policy.MaxCount:
+				reason = "count limit reached"
+			case policy.MaxSize > 0 && size+c.size > policy.MaxSize:
+				reason = "size limit reached"
+			}
+
+			if reason == "" {
+				count++
+				size += c.size
+				continue
+			}
+
+			if err := db.purge(conn, c.id); err != nil {
+				errorChannel <- err
+				return
+			}
+			rule.log().Info("Retention: voicemail purged", "voicemail", c.id, "caller", c.caller,
+				"date", c.date.Format(time.RFC3339), "reason", reason)
+			purged++
+		}
+
+		errorChannel <- nil
+	}
+
+	err := countError("retention", <-errorChannel)
+	return purged, err
+}
+
+// log returns a logger for messages about the rule.
+func (rule RetentionRule) log() *utils.Log {
+	if rule.Name == "" {
+		return logger
+	}
+	return logger.With("mailbox", rule.Name)
+}
+
+func (db Database) retentionCandidates(conn *sql.DB, filter Filter) ([]retentionCandidate, error) {
+	where, args := filter.conditions(AllFolders)
+	rows, err := conn.Query(`SELECT id, caller, date, voicemail, starred
+                                 FROM voicemail WHERE `+where+` ORDER BY julianday(date) DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	candidates := []retentionCandidate{}
+	for rows.Next() {
+		var c retentionCandidate
+		var date, voicemailPath string
+		if err := rows.Scan(&c.id, &c.caller, &date, &voicemailPath, &c.starred); err != nil {
+			return nil, err
+		}
+
+		c.date, err = time.Parse(dateFormat, date)
+		if err != nil {
+			return nil, err
+		}
+
+		if info, err := os.Stat(path.Join(db.storageDir, path.Base(voicemailPath))); err == nil {
+			c.size = info.Size()
+		}
+
+		candidates = append(candidates, c)
+	}
+
+	return candidates, rows.Err()
+}
+
+// StartRetention enforces the rules now and then periodically in the
+// background until the returned function is called.
+func (db Database) StartRetention(interval time.Duration, rules ...RetentionRule) func() {
+	enabled := []RetentionRule{}
+	for _, rule := range rules {
+		if rule.Policy.Enabled() {
+			enabled = append(enabled, rule)
+		}
+	}
+	if len(enabled) == 0 {
+		return func() {}
+	}
+
+	stop := make(chan struct{})
+	go func() {
+		for {
+			for _, rule := range enabled {
+				if n, err := db.EnforceRetention(rule); err != nil {
+					rule.log().Error("Retention: unable to enforce policy", "error", err)
+				} else if n > 0 {
+					rule.log().Info("Retention: voicemails purged", "count", n)
+				}
+			}
+			select {
+			case <-stop:
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+	return func() { close(stop) }
+}
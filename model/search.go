@@ -0,0 +1,222 @@
+package model
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ReadState filters voicemails by whether they have been listened to.
+type ReadState int
+
+const (
+	AnyReadState ReadState = iota
+	OnlyRead
+	OnlyUnread
+)
+
+// Filter restricts the voicemails returned by GetPage.  The zero value
+// matches all voicemails, and every set field narrows the result.
+type Filter struct {
+	// Text is a SQLite full-text query matched against the caller,
+	// the notes and the transcript.
+	Text   string
+	Caller string
+	Called string
+	// From and Until limit the date range.  From is inclusive, Until
+	// is exclusive.
+	From  time.Time
+	Until time.Time
+	// MinDuration and MaxDuration limit the duration range, both are
+	// inclusive.
+	MinDuration time.Duration
+	MaxDuration time.Duration
+	Read        ReadState
+	// Tags lists tags that all matching voicemails have.
+	Tags []string
+	// Numbers and ExcludeNumbers restrict the called number to, or
+	// exclude, a set of exact numbers.  Mailboxes are filtered this way.
+	Numbers        []string
+	ExcludeNumbers []string
+	// Permissions restrict the result to the voicemails a user may
+	// access.  Nil means no restriction.
+	Permissions *Permissions
+}
+
+// ErrInvalidTag is returned for empty tags and tags containing commas.
+var ErrInvalidTag = errors.New("invalid tag")
+
+// conditions returns the SQL conditions and arguments that select the
+// voicemails in folder matching the filter.
+func (f Filter) conditions(folder Folder) (string, []interface{}) {
+	conditions := []string{folderConditions[folder]}
+	args := []interface{}{}
+
+	if f.Text != "" {
+		conditions = append(conditions,
+			"id IN (SELECT docid FROM voicemail_search WHERE voicemail_search MATCH ?)")
+		args = append(args, f.Text)
+	}
+	if f.Caller != "" {
+		conditions = append(conditions, `caller LIKE ? ESCAPE '\'`)
+		args = append(args, containsPattern(f.Caller))
+	}
+	if f.Called != "" {
+		conditions = append(conditions, `called LIKE ? ESCAPE '\'`)
+		args = append(args, containsPattern(f.Called))
+	}
+	if !f.From.IsZero() {
+		conditions = append(conditions, "julianday(date) >= julianday(?)")
+		args = append(args, f.From.Format(dateFormat))
+	}
+	if !f.Until.IsZero() {
+		conditions = append(conditions, "julianday(date) < julianday(?)")
+		args = append(args, f.Until.Format(dateFormat))
+	}
+	if f.MinDuration > 0 {
+		conditions = append(conditions, "duration >= ?")
+		args = append(args, f.MinDuration.Seconds())
+	}
+	if f.MaxDuration > 0 {
+		conditions = append(conditions, "duration <= ?")
+		args = append(args, f.MaxDuration.Seconds())
+	}
+	switch f.Read {
+	case OnlyRead:
+		conditions = append(conditions, "read = 1")
+	case OnlyUnread:
+		conditions = append(conditions, "read = 0")
+	}
+	if len(f.Numbers) > 0 {
+		conditions = append(conditions, "called IN ("+placeholders(len(f.Numbers))+")")
+		for _, number := range f.Numbers {
+			args = append(args, number)
+		}
+	}
+	if len(f.ExcludeNumbers) > 0 {
+		conditions = append(conditions, "called NOT IN ("+placeholders(len(f.ExcludeNumbers))+")")
+		for _, number := range f.ExcludeNumbers {
+			args = append(args, number)
+		}
+	}
+	if f.Permissions != nil {
+		condition, permissionArgs := f.Permissions.conditions()
+		conditions = append(conditions, condition)
+		args = append(args, permissionArgs...)
+	}
+	for _, tag := range f.Tags {
+		conditions = append(conditions,
+			"EXISTS (SELECT 1 FROM tag WHERE tag.voicemail = voicemail.id AND tag.name = ?)")
+		args = append(args, tag)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern returns a LIKE pattern with backslash as escape
+// character that matches strings containing s.
+func containsPattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// MatchWords builds a full-text query that matches all words in s as
+// prefixes, treating any query syntax in s literally.
+func MatchWords(s string) string {
+	terms := []string{}
+	for _, word := range strings.Fields(strings.Replace(s, `"`, " ", -1)) {
+		terms = append(terms, `"`+word+`*"`)
+	}
+	return strings.Join(terms, " ")
+}
+
+// ParseTags splits a comma separated list of tags.
+func ParseTags(s string) []string {
+	tags := []string{}
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// Count returns the number of voicemails in folder matching the
+// filter.
+func (db Database) Count(folder Folder, filter Filter) (int, error) {
+	where, args := filter.conditions(folder)
+	errorChannel := make(chan error)
+	var n int
+
+	db.channel <- func(conn *sql.DB) {
+		errorChannel <- conn.QueryRow("SELECT count(*) FROM voicemail WHERE "+where, args...).Scan(&n)
+	}
+
+	return n, <-errorChannel
+}
+
+func (db Database) SetNotes(id int, notes string) error {
+	return db.update(id, "notes = ?", notes)
+}
+
+func (db Database) SetTranscript(id int, transcript string) error {
+	return db.update(id, "transcript = ?", transcript)
+}
+
+// SetTags replaces the tags of a voicemail.
+func (db Database) SetTags(id int, tags []string) error {
+	for _, tag := range tags {
+		if tag == "" || strings.Contains(tag, ",") {
+			return ErrInvalidTag
+		}
+	}
+
+	errorChannel := make(chan error)
+
+	db.channel <- func(conn *sql.DB) {
+		tx, err := conn.Begin()
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+
+		var exists bool
+		if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM voicemail WHERE id = ?)", id).Scan(&exists); err != nil {
+			tx.Rollback()
+			errorChannel <- err
+			return
+		} else if !exists {
+			tx.Rollback()
+			errorChannel <- ErrNoVoicemail
+			return
+		}
+
+		if _, err := tx.Exec("DELETE FROM tag WHERE voicemail = ?", id); err != nil {
+			tx.Rollback()
+			errorChannel <- err
+			return
+		}
+		for _, tag := range tags {
+			if _, err := tx.Exec("INSERT OR IGNORE INTO tag (voicemail, name) VALUES (?, ?)", id, tag); err != nil {
+				tx.Rollback()
+				errorChannel <- err
+				return
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			errorChannel <- err
+			return
+		}
+		db.publishVoicemail(conn, VoicemailChanged, id)
+		errorChannel <- nil
+	}
+
+	return countError("update", <-errorChannel)
+}
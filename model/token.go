@@ -0,0 +1,274 @@
+package model
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Scope is a permission granted to an API token.  Each scope in
+// AllScopes includes the ones before it.
+type Scope string
+
+const (
+	// ScopeRead allows to list, search and play voicemails.
+	ScopeRead Scope = "read"
+	// ScopeWrite additionally allows to change and delete voicemails.
+	ScopeWrite Scope = "write"
+	// ScopeAdmin additionally allows to manage the user's API tokens.
+	ScopeAdmin Scope = "admin"
+	// ScopeFeed only allows to read the feeds.  Feed URLs contain the
+	// token, so it grants nothing else.
+	ScopeFeed Scope = "feed"
+)
+
+var AllScopes = []Scope{ScopeRead, ScopeWrite, ScopeAdmin}
+
+// TokenScopes are the scopes an API token can be created with.
+var TokenScopes = []Scope{ScopeRead, ScopeWrite, ScopeAdmin, ScopeFeed}
+
+// tokenPrefix makes API tokens recognizable, e.g. in configuration
+// files.
+const tokenPrefix = "vm_"
+
+var (
+	ErrNoToken          = errors.New("no such API token")
+	ErrInvalidScope     = errors.New("invalid scope, expected read, write, admin or feed")
+	ErrInvalidTokenName = errors.New("invalid API token name")
+)
+
+func validScope(scope Scope) bool {
+	for _, s := range TokenScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseScopes checks scope names.
+func ParseScopes(names []string) ([]Scope, error) {
+	scopes := []Scope{}
+	for _, name := range names {
+		if !validScope(Scope(name)) {
+			return nil, ErrInvalidScope
+		}
+		scopes = append(scopes, Scope(name))
+	}
+	return scopes, nil
+}
+
+// rank returns the position of a scope in AllScopes, or -1.
+func rank(scope Scope) int {
+	for i, s := range AllScopes {
+		if s == scope {
+			return i
+		}
+	}
+	return -1
+}
+
+func hasScope(scopes []Scope, scope Scope) bool {
+	for _, granted := range scopes {
+		if granted == scope || (rank(scope) >= 0 && rank(scope) <= rank(granted)) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether the session was granted scope.
+func (s Session) HasScope(scope Scope) bool {
+	return hasScope(s.Scopes, scope)
+}
+
+func joinScopes(scopes []Scope) string {
+	names := make([]string, len(scopes))
+	for i, scope := range scopes {
+		names[i] = string(scope)
+	}
+	return strings.Join(names, ",")
+}
+
+func splitScopes(s string) []Scope {
+	scopes := []Scope{}
+	for _, name := range strings.Split(s, ",") {
+		if name != "" {
+			scopes = append(scopes, Scope(name))
+		}
+	}
+	return scopes
+}
+
+// Token is an API token that lets programs act as a user.
+type Token struct {
+	Id       int
+	Name     string
+	User     string
+	Scopes   []Scope
+	Created  time.Time
+	LastUsed time.Time
+}
+
+// CreateToken adds an API token for a user and returns it together
+// with its secret.  Only a hash of the secret is stored, it cannot be
+// shown again.
+func (db Database) CreateToken(userName string, name string, scopes []Scope) (Token, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, "\t\r\n") {
+		return Token{}, "", ErrInvalidTokenName
+	}
+	if len(scopes) == 0 {
+		return Token{}, "", ErrInvalidScope
+	}
+	for _, scope := range scopes {
+		if !validScope(scope) || (scope == ScopeFeed && len(scopes) > 1) {
+			return Token{}, "", ErrInvalidScope
+		}
+	}
+
+	secret, err := randomToken()
+	if err != nil {
+		return Token{}, "", err
+	}
+	secret = tokenPrefix + secret
+
+	token := Token{Name: name, User: userName, Scopes: scopes, Created: time.Now()}
+	errorChannel := make(chan error)
+
+	db.channel <- func(conn *sql.DB) {
+		var user int
+		err := conn.QueryRow("SELECT id FROM user WHERE name = ?", userName).Scan(&user)
+		if err == sql.ErrNoRows {
+			errorChannel <- ErrNoUser
+			return
+		} else if err != nil {
+			errorChannel <- err
+			return
+		}
+
+		res, err := conn.Exec(`INSERT INTO api_token (user, name, token, scopes, created)
+                                       VALUES (?, ?, ?, ?, ?)`,
+			user, name, hashToken(secret), joinScopes(scopes), token.Created.Format(dateFormat))
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+
+		id, err := res.LastInsertId()
+		token.Id = int(id)
+		errorChannel <- err
+	}
+
+	if err := <-errorChannel; err != nil {
+		return Token{}, "", err
+	}
+	return token, secret, nil
+}
+
+// GetTokens returns the API tokens of a user, or of all users if
+// userName is empty.
+func (db Database) GetTokens(userName string) ([]Token, error) {
+	errorChannel := make(chan error)
+	tokens := []Token{}
+
+	db.channel <- func(conn *sql.DB) {
+		rows, err := conn.Query(`SELECT api_token.id, api_token.name, user.name, scopes, created, last_used
+                                         FROM api_token JOIN user ON api_token.user = user.id
+                                         WHERE ? = '' OR user.name = ?
+                                         ORDER BY user.name, api_token.id`, userName, userName)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var token Token
+			var scopes, created string
+			var lastUsed sql.NullString
+			if err := rows.Scan(&token.Id, &token.Name, &token.User, &scopes, &created, &lastUsed); err != nil {
+				errorChannel <- err
+				return
+			}
+			token.Scopes = splitScopes(scopes)
+			if token.Created, err = time.Parse(dateFormat, created); err != nil {
+				errorChannel <- err
+				return
+			}
+			if lastUsed.Valid {
+				if token.LastUsed, err = time.Parse(dateFormat, lastUsed.String); err != nil {
+					errorChannel <- err
+					return
+				}
+			}
+			tokens = append(tokens, token)
+		}
+		errorChannel <- rows.Err()
+	}
+
+	return tokens, <-errorChannel
+}
+
+// RevokeToken deletes an API token.  If userName is not empty, the
+// token must belong to that user.
+func (db Database) RevokeToken(id int, userName string) error {
+	errorChannel := make(chan error)
+
+	db.channel <- func(conn *sql.DB) {
+		res, err := conn.Exec(`DELETE FROM api_token WHERE id = ? AND
+                                       (? = '' OR user = (SELECT id FROM user WHERE name = ?))`,
+			id, userName, userName)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+
+		if n, err := res.RowsAffected(); err != nil {
+			errorChannel <- err
+		} else if n == 0 {
+			errorChannel <- ErrNoToken
+		} else {
+			errorChannel <- nil
+		}
+	}
+
+	return <-errorChannel
+}
+
+// AuthenticateToken returns a session for the user of an API token,
+// limited to the token's scopes.
+func (db Database) AuthenticateToken(secret string) (Session, error) {
+	if !strings.HasPrefix(secret, tokenPrefix) {
+		return Session{}, ErrNoToken
+	}
+
+	errorChannel := make(chan error)
+	var session Session
+
+	db.channel <- func(conn *sql.DB) {
+		var id int
+		var scopes string
+		var mailboxes sql.NullString
+		err := conn.QueryRow(`SELECT api_token.id, api_token.scopes, user.id, user.name, `+userMailboxes+`
+                                      FROM api_token JOIN user ON api_token.user = user.id
+                                      WHERE api_token.token = ?`, hashToken(secret)).Scan(
+			&id, &scopes, &session.User.Id, &session.User.Name, &mailboxes)
+		if err == sql.ErrNoRows {
+			errorChannel <- ErrNoToken
+			return
+		} else if err != nil {
+			errorChannel <- err
+			return
+		}
+		session.User.Mailboxes = splitMailboxes(mailboxes)
+		session.Scopes = splitScopes(scopes)
+
+		_, err = conn.Exec("UPDATE api_token SET last_used = ? WHERE id = ?",
+			time.Now().Format(dateFormat), id)
+		errorChannel <- err
+	}
+
+	return session, <-errorChannel
+}
@@ -0,0 +1,401 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"../external/crypto/bcrypt"
+)
+
+type User struct {
+	Id   int
+	Name string
+	// Mailboxes lists the mailboxes and called numbers the user may
+	// access, or AllMailboxes.
+	Mailboxes []string
+}
+
+// userMailboxes selects the mailboxes of the user in the query.
+const userMailboxes = `(SELECT group_concat(mailbox, ',') FROM user_mailbox
+                         WHERE user_mailbox.user = user.id)`
+
+func splitMailboxes(mailboxes sql.NullString) []string {
+	if !mailboxes.Valid || mailboxes.String == "" {
+		return []string{}
+	}
+	return strings.Split(mailboxes.String, ",")
+}
+
+func validMailboxGrants(mailboxes []string) bool {
+	for _, mailbox := range mailboxes {
+		if mailbox == "" || strings.ContainsAny(mailbox, ", \t\r\n") {
+			return false
+		}
+	}
+	return true
+}
+
+func setUserMailboxes(tx *sql.Tx, id int, mailboxes []string) error {
+	if _, err := tx.Exec("DELETE FROM user_mailbox WHERE user = ?", id); err != nil {
+		return err
+	}
+	for _, mailbox := range mailboxes {
+		_, err := tx.Exec("INSERT OR IGNORE INTO user_mailbox (user, mailbox) VALUES (?, ?)", id, mailbox)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Session is a login of a user in the web interface.
+type Session struct {
+	// Token identifies the session in the session cookie.  Only its
+	// hash is stored in the database.
+	Token string
+	// CSRFToken must be sent along with every form submission.
+	CSRFToken string
+	User      User
+	Expires   time.Time
+	// Scopes limits what the session may do.  Logins have all scopes,
+	// API tokens the ones they were created with.
+	Scopes []Scope
+}
+
+var (
+	ErrNoUser             = errors.New("no such user")
+	ErrUserExists         = errors.New("user already exists")
+	ErrInvalidUserName    = errors.New("invalid user name")
+	ErrPasswordTooShort   = errors.New("password must have at least 8 characters")
+	ErrInvalidCredentials = errors.New("invalid user name or password")
+	ErrNoSession          = errors.New("no such session")
+	ErrInvalidMailbox     = errors.New("invalid mailbox name")
+)
+
+const minPasswordLength = 8
+
+// randomToken returns a random URL-safe token with 256 bits of entropy.
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func hashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", ErrPasswordTooShort
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(hash), err
+}
+
+// CreateUser adds a user that may access the given mailboxes.
+func (db Database) CreateUser(name string, password string, mailboxes []string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, " \t\r\n") {
+		return ErrInvalidUserName
+	}
+	if !validMailboxGrants(mailboxes) {
+		return ErrInvalidMailbox
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	errorChannel := make(chan error)
+
+	db.channel <- func(conn *sql.DB) {
+		tx, err := conn.Begin()
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+
+		var exists bool
+		err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM user WHERE name = ?)", name).Scan(&exists)
+		if err == nil && exists {
+			err = ErrUserExists
+		}
+
+		var res sql.Result
+		if err == nil {
+			res, err = tx.Exec("INSERT INTO user (name, password) VALUES (?, ?)", name, hash)
+		}
+
+		var id int64
+		if err == nil {
+			id, err = res.LastInsertId()
+		}
+		if err == nil {
+			err = setUserMailboxes(tx, int(id), mailboxes)
+		}
+
+		if err != nil {
+			tx.Rollback()
+			errorChannel <- err
+			return
+		}
+		errorChannel <- tx.Commit()
+	}
+
+	return <-errorChannel
+}
+
+// SetPassword changes the password of a user and ends all of the user's
+// sessions.
+func (db Database) SetPassword(name string, password string) error {
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	errorChannel := make(chan error)
+
+	db.channel <- func(conn *sql.DB) {
+		res, err := conn.Exec("UPDATE user SET password = ? WHERE name = ?", hash, name)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+
+		if n, err := res.RowsAffected(); err != nil {
+			errorChannel <- err
+			return
+		} else if n == 0 {
+			errorChannel <- ErrNoUser
+			return
+		}
+
+		_, err = conn.Exec(`DELETE FROM session
+                                    WHERE user = (SELECT id FROM user WHERE name = ?)`, name)
+		errorChannel <- err
+	}
+
+	return <-errorChannel
+}
+
+// SetUserMailboxes replaces the mailboxes a user may access.
+func (db Database) SetUserMailboxes(name string, mailboxes []string) error {
+	if !validMailboxGrants(mailboxes) {
+		return ErrInvalidMailbox
+	}
+
+	errorChannel := make(chan error)
+
+	db.channel <- func(conn *sql.DB) {
+		tx, err := conn.Begin()
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+
+		var id int
+		err = tx.QueryRow("SELECT id FROM user WHERE name = ?", name).Scan(&id)
+		if err == sql.ErrNoRows {
+			err = ErrNoUser
+		}
+		if err == nil {
+			err = setUserMailboxes(tx, id, mailboxes)
+		}
+
+		if err != nil {
+			tx.Rollback()
+			errorChannel <- err
+			return
+		}
+		errorChannel <- tx.Commit()
+	}
+
+	return <-errorChannel
+}
+
+func (db Database) DeleteUser(name string) error {
+	errorChannel := make(chan error)
+
+	db.channel <- func(conn *sql.DB) {
+		var id int
+		err := conn.QueryRow("SELECT id FROM user WHERE name = ?", name).Scan(&id)
+		if err == sql.ErrNoRows {
+			errorChannel <- ErrNoUser
+			return
+		} else if err != nil {
+			errorChannel <- err
+			return
+		}
+
+		if _, err := conn.Exec("DELETE FROM session WHERE user = ?", id); err != nil {
+			errorChannel <- err
+			return
+		}
+		if _, err := conn.Exec("DELETE FROM api_token WHERE user = ?", id); err != nil {
+			errorChannel <- err
+			return
+		}
+		if _, err := conn.Exec("DELETE FROM user_mailbox WHERE user = ?", id); err != nil {
+			errorChannel <- err
+			return
+		}
+		_, err = conn.Exec("DELETE FROM user WHERE id = ?", id)
+		errorChannel <- err
+	}
+
+	return <-errorChannel
+}
+
+func (db Database) GetUsers() ([]User, error) {
+	errorChannel := make(chan error)
+	users := []User{}
+
+	db.channel <- func(conn *sql.DB) {
+		rows, err := conn.Query("SELECT id, name, " + userMailboxes + " FROM user ORDER BY name")
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var user User
+			var mailboxes sql.NullString
+			if err := rows.Scan(&user.Id, &user.Name, &mailboxes); err != nil {
+				errorChannel <- err
+				return
+			}
+			user.Mailboxes = splitMailboxes(mailboxes)
+			users = append(users, user)
+		}
+		errorChannel <- rows.Err()
+	}
+
+	return users, <-errorChannel
+}
+
+// Authenticate checks a user's password.
+func (db Database) Authenticate(name string, password string) (User, error) {
+	errorChannel := make(chan error)
+	var user User
+	var hash string
+
+	db.channel <- func(conn *sql.DB) {
+		var mailboxes sql.NullString
+		err := conn.QueryRow("SELECT id, name, password, "+userMailboxes+" FROM user WHERE name = ?",
+			name).Scan(&user.Id, &user.Name, &hash, &mailboxes)
+		if err == sql.ErrNoRows {
+			err = ErrInvalidCredentials
+		}
+		user.Mailboxes = splitMailboxes(mailboxes)
+		errorChannel <- err
+	}
+
+	if err := <-errorChannel; err != nil {
+		if err == ErrInvalidCredentials {
+			// Spend the same time as for existing users.
+			bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		}
+		return User{}, err
+	}
+
+	// Compare outside of the database goroutine, bcrypt is slow.
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return User{}, ErrInvalidCredentials
+	}
+
+	return user, nil
+}
+
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+// CreateSession starts a new session for a user.
+func (db Database) CreateSession(user User, lifetime time.Duration) (Session, error) {
+	token, err := randomToken()
+	if err != nil {
+		return Session{}, err
+	}
+	csrf, err := randomToken()
+	if err != nil {
+		return Session{}, err
+	}
+
+	session := Session{
+		Token:     token,
+		CSRFToken: csrf,
+		User:      user,
+		Expires:   time.Now().Add(lifetime),
+		Scopes:    AllScopes,
+	}
+
+	errorChannel := make(chan error)
+
+	db.channel <- func(conn *sql.DB) {
+		if _, err := conn.Exec("DELETE FROM session WHERE julianday(expires) < julianday(?)",
+			time.Now().Format(dateFormat)); err != nil {
+			errorChannel <- err
+			return
+		}
+
+		_, err := conn.Exec("INSERT INTO session (token, user, csrf, expires) VALUES (?, ?, ?, ?)",
+			hashToken(token), user.Id, csrf, session.Expires.Format(dateFormat))
+		errorChannel <- err
+	}
+
+	return session, <-errorChannel
+}
+
+// GetSession returns the unexpired session for a token.
+func (db Database) GetSession(token string) (Session, error) {
+	errorChannel := make(chan error)
+	session := Session{Token: token, Scopes: AllScopes}
+
+	db.channel <- func(conn *sql.DB) {
+		var expires string
+		var mailboxes sql.NullString
+		err := conn.QueryRow(`SELECT user.id, user.name, `+userMailboxes+`,
+                                             session.csrf, session.expires
+                                      FROM session JOIN user ON session.user = user.id
+                                      WHERE session.token = ?`, hashToken(token)).Scan(
+			&session.User.Id, &session.User.Name, &mailboxes, &session.CSRFToken, &expires)
+		session.User.Mailboxes = splitMailboxes(mailboxes)
+		if err == sql.ErrNoRows {
+			errorChannel <- ErrNoSession
+			return
+		} else if err != nil {
+			errorChannel <- err
+			return
+		}
+
+		session.Expires, err = time.Parse(dateFormat, expires)
+		if err == nil && session.Expires.Before(time.Now()) {
+			err = ErrNoSession
+		}
+		errorChannel <- err
+	}
+
+	return session, <-errorChannel
+}
+
+func (db Database) DeleteSession(token string) error {
+	errorChannel := make(chan error)
+
+	db.channel <- func(conn *sql.DB) {
+		_, err := conn.Exec("DELETE FROM session WHERE token = ?", hashToken(token))
+		errorChannel <- err
+	}
+
+	return <-errorChannel
+}
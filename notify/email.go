@@ -0,0 +1,215 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"path"
+	"strings"
+	"time"
+
+	"../model"
+)
+
+// EmailConfig describes how voicemails are forwarded by email.
+type EmailConfig struct {
+	// Relay is the host:port of the SMTP server that sends the
+	// messages.  STARTTLS is used if the server offers it.
+	Relay    string `json:"relay"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	From     string `json:"from"`
+	// Recipients receive a message with the recording for every new
+	// voicemail.
+	Recipients []Recipient `json:"recipients"`
+}
+
+// Recipient is an email address that voicemails are forwarded to.
+type Recipient struct {
+	Address string `json:"address"`
+	// Mailboxes restricts the recipient to the voicemails in these
+	// mailboxes or for these called numbers.
+	Mailboxes []string `json:"mailboxes"`
+}
+
+// LoadEmailConfig reads the email forwarding configuration from a JSON
+// file.
+func LoadEmailConfig(file string) (EmailConfig, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return EmailConfig{}, err
+	}
+
+	config, err := ParseEmailConfig(data)
+	if err != nil {
+		return EmailConfig{}, fmt.Errorf("%s: %v", file, err)
+	}
+	return config, nil
+}
+
+// ParseEmailConfig reads the email forwarding configuration from JSON.
+func ParseEmailConfig(data []byte) (EmailConfig, error) {
+	var config EmailConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return EmailConfig{}, err
+	}
+
+	if _, _, err := net.SplitHostPort(config.Relay); err != nil {
+		return EmailConfig{}, fmt.Errorf("invalid relay %q, expected host:port", config.Relay)
+	}
+	if !strings.Contains(config.From, "@") {
+		return EmailConfig{}, fmt.Errorf("invalid sender address %q", config.From)
+	}
+	for _, recipient := range config.Recipients {
+		if !strings.Contains(recipient.Address, "@") {
+			return EmailConfig{}, fmt.Errorf("invalid recipient address %q", recipient.Address)
+		}
+	}
+
+	return config, nil
+}
+
+// StartEmail forwards every new voicemail to the configured recipients
+// until the returned function is called, which waits for the messages
+// being sent.
+func StartEmail(db model.Database, config EmailConfig, mailboxes model.Mailboxes, voicemailDir string, publicURL string) func() {
+	if len(config.Recipients) == 0 {
+		return func() {}
+	}
+
+	return subscribe(db, func(voicemail model.Voicemail, d *deliveries) {
+		for _, recipient := range config.Recipients {
+			if grants(mailboxes, recipient.Mailboxes, voicemail.Called) {
+				to := recipient.Address
+				d.start(func() { forwardEmail(db, d.stop, config, to, voicemail, mailboxes, voicemailDir, publicURL) })
+			}
+		}
+	})
+}
+
+func forwardEmail(db model.Database, stop <-chan struct{}, config EmailConfig, to string, voicemail model.Voicemail,
+	mailboxes model.Mailboxes, voicemailDir string, publicURL string) {
+
+	audio, err := ioutil.ReadFile(path.Join(voicemailDir, path.Base(voicemail.VoicemailPath)))
+	if err != nil {
+		logger.Error("Unable to read voicemail for forwarding", "voicemail", voicemail.Id, "error", err)
+		return
+	}
+
+	message, err := emailMessage(config.From, to, voicemail, mailboxes, publicURL, audio)
+	if err != nil {
+		logger.Error("Unable to compose message", "voicemail", voicemail.Id, "error", err)
+		return
+	}
+
+	deliver(db, stop, "email", to, voicemail.Id, func() (bool, error) {
+		return sendEmail(config, to, message)
+	})
+}
+
+// sendEmail sends a message through the relay and reports whether a
+// failure is temporary.
+func sendEmail(config EmailConfig, to string, message []byte) (bool, error) {
+	var auth smtp.Auth
+	if config.Username != "" {
+		host, _, _ := net.SplitHostPort(config.Relay)
+		auth = smtp.PlainAuth("", config.Username, config.Password, host)
+	}
+
+	err := smtp.SendMail(config.Relay, auth, config.From, []string{to}, message)
+	return temporaryEmailError(err), err
+}
+
+// temporaryEmailError reports whether sending may succeed later: after
+// network errors and replies with 4xx codes.  Other errors, e.g. an
+// invalid certificate, an address with a line break or authentication
+// refused without TLS, fail again.
+func temporaryEmailError(err error) bool {
+	var protoErr *textproto.Error
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return false
+	case errors.As(err, &protoErr):
+		return protoErr.Code < 500
+	case errors.As(err, &netErr):
+		return true
+	default:
+		return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+	}
+}
+
+// emailMessage composes a message with the details of a voicemail and
+// the recording as attachment.
+func emailMessage(from string, to string, voicemail model.Voicemail, mailboxes model.Mailboxes,
+	publicURL string, audio []byte) ([]byte, error) {
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	duration := voicemail.Duration.String()
+	header := []string{
+		"From: " + from,
+		"To: " + to,
+		"Subject: " + mime.QEncoding.Encode("utf-8",
+			fmt.Sprintf("Neue Nachricht von %s (%s)", voicemail.Caller, duration)),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/mixed; boundary=" + w.Boundary(),
+	}
+	buf.WriteString(strings.Join(header, "\r\n") + "\r\n\r\n")
+
+	called := voicemail.Called
+	if mailbox, ok := mailboxes.ForNumber(voicemail.Called); ok {
+		called = fmt.Sprintf("%s (%s)", voicemail.Called, mailbox.Title)
+	}
+	text := fmt.Sprintf("Neue Nachricht auf dem Anrufbeantworter\r\n\r\n"+
+		"Von:   %s\r\nAn:    %s\r\nDatum: %s\r\nDauer: %s\r\n",
+		voicemail.Caller, called, voicemail.Date.Format("02.01.2006 15:04"), duration)
+	if publicURL != "" {
+		text += fmt.Sprintf("\r\nAnhören: %s/voicemail/%s\r\n",
+			strings.TrimRight(publicURL, "/"), path.Base(voicemail.VoicemailPath))
+	}
+
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	qp := quotedprintable.NewWriter(part)
+	qp.Write([]byte(text))
+	qp.Close()
+
+	name := voicemail.Date.Format("20060102-1504") + "-" + voicemail.Caller + ".mp3"
+	part, err = w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType("audio/mpeg", map[string]string{"name": name})},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": name})},
+	})
+	if err != nil {
+		return nil, err
+	}
+	encoded := base64.StdEncoding.EncodeToString(audio)
+	for len(encoded) > 76 {
+		part.Write([]byte(encoded[:76] + "\r\n"))
+		encoded = encoded[76:]
+	}
+	part.Write([]byte(encoded + "\r\n"))
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
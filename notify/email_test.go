@@ -0,0 +1,196 @@
+package notify
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"../model"
+)
+
+// fakeSMTPServer accepts messages and sends them to messages.  The
+// first failures recipients are rejected with a temporary error.
+func fakeSMTPServer(t *testing.T, failures int, messages chan<- string) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			in := bufio.NewReader(conn)
+			fmt.Fprint(conn, "220 localhost ESMTP test\r\n")
+			for {
+				line, err := in.ReadString('\n')
+				if err != nil {
+					break
+				}
+				switch cmd := strings.ToUpper(strings.Fields(line + " x")[0]); cmd {
+				case "EHLO", "HELO":
+					fmt.Fprint(conn, "250-localhost\r\n250 8BITMIME\r\n")
+				case "RCPT":
+					if failures > 0 {
+						failures--
+						fmt.Fprint(conn, "451 4.3.0 Try again later\r\n")
+					} else {
+						fmt.Fprint(conn, "250 2.1.5 Ok\r\n")
+					}
+				case "DATA":
+					fmt.Fprint(conn, "354 Go ahead\r\n")
+					var message bytes.Buffer
+					for {
+						line, err := in.ReadString('\n')
+						if err != nil || line == ".\r\n" {
+							break
+						}
+						message.WriteString(strings.TrimPrefix(line, "."))
+					}
+					fmt.Fprint(conn, "250 2.0.0 Ok: queued\r\n")
+					messages <- message.String()
+				case "QUIT":
+					fmt.Fprint(conn, "221 2.0.0 Bye\r\n")
+				default:
+					fmt.Fprint(conn, "250 2.0.0 Ok\r\n")
+				}
+			}
+			conn.Close()
+		}
+	}()
+
+	return l
+}
+
+func TestEmail(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	retryBackoff = 10 * time.Millisecond
+
+	messages := make(chan string, 10)
+	l := fakeSMTPServer(t, 1, messages)
+	defer l.Close()
+
+	mailboxes := model.Mailboxes{
+		{Name: "home", Title: "Zuhause", Numbers: []string{"111"}},
+		{Name: "office", Title: "Büro", Numbers: []string{"222"}},
+	}
+	config := EmailConfig{
+		Relay:      l.Addr().String(),
+		From:       "voicemail@example.com",
+		Recipients: []Recipient{{Address: "alice@example.com", Mailboxes: []string{"office"}}},
+	}
+	StartEmail(db, config, mailboxes, tempDir, "https://voicemail.example.com")
+
+	date := time.Date(2026, 10, 19, 9, 30, 0, 0, time.Local)
+	for _, called := range []string{"111", "222"} {
+		err := db.AddVoicemail(model.Voicemail{
+			Caller: "0301234567", Called: called, Date: date, Duration: 12 * time.Second,
+		}, []byte("audio for "+called))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var raw string
+	select {
+	case raw = <-messages:
+	case <-time.After(2 * time.Second):
+		t.Fatal("No message received")
+	}
+	select {
+	case m := <-messages:
+		t.Fatalf("Unexpected message: %s", m)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if msg.Header.Get("To") != "alice@example.com" || subject != "Neue Nachricht von 0301234567 (12s)" {
+		t.Errorf("Unexpected header: %v", msg.Header)
+	}
+
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := multipart.NewReader(msg.Body, params["boundary"])
+
+	part, err := r.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, _ := ioutil.ReadAll(quotedprintable.NewReader(part))
+	for _, s := range []string{"Von:   0301234567", "An:    222 (Büro)", "Datum: 19.10.2026 09:30",
+		"https://voicemail.example.com/voicemail/"} {
+		if !strings.Contains(string(text), s) {
+			t.Errorf("%q missing in text: %s", s, text)
+		}
+	}
+
+	part, err = r.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if part.FileName() != "20261019-0930-0301234567.mp3" {
+		t.Errorf("Unexpected attachment name %q", part.FileName())
+	}
+	attachment, _ := ioutil.ReadAll(part)
+	if !bytes.Contains(attachment, []byte("YXVkaW8gZm9yIDIyMg==")) {
+		t.Errorf("Unexpected attachment: %s", attachment)
+	}
+
+	var deliveries []model.Delivery
+	for i := 0; i < 100 && len(deliveries) < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+		if deliveries, err = db.GetDeliveries(10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(deliveries) != 2 || !deliveries[0].Success || deliveries[1].Success ||
+		deliveries[0].Channel != "email" || deliveries[0].Target != "alice@example.com" {
+		t.Errorf("Unexpected delivery log: %+v", deliveries)
+	}
+}
+
+func TestTemporaryEmailError(t *testing.T) {
+	tests := []struct {
+		err       error
+		temporary bool
+	}{
+		{nil, false},
+		{&textproto.Error{Code: 421, Msg: "try again later"}, true},
+		{&textproto.Error{Code: 550, Msg: "no such user"}, false},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, true},
+		{io.EOF, true},
+		{errors.New("unencrypted connection"), false},
+		{x509.UnknownAuthorityError{}, false},
+		{errors.New("smtp: A line must not contain CR or LF"), false},
+	}
+	for _, test := range tests {
+		if temporary := temporaryEmailError(test.err); temporary != test.temporary {
+			t.Errorf("%v: expected temporary %v, got %v", test.err, test.temporary, temporary)
+		}
+	}
+}
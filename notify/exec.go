@@ -0,0 +1,306 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"../model"
+)
+
+// Hook events.  HookTrashed is the deletion in the web interface, the
+// API and the command line, HookDeleted the permanent one.
+const (
+	HookAdded   = "added"
+	HookFailed  = "failed"
+	HookTrashed = "trashed"
+	HookDeleted = "deleted"
+)
+
+// hookEvent returns the hook event for a voicemail event.
+func hookEvent(event model.Event) (string, bool) {
+	switch {
+	case event.Type == model.VoicemailAdded:
+		return HookAdded, true
+	case event.Type == model.VoicemailFailed:
+		return HookFailed, true
+	case event.Type == model.VoicemailChanged && event.Trashed:
+		return HookTrashed, true
+	case event.Type == model.VoicemailPurged:
+		return HookDeleted, true
+	}
+	return "", false
+}
+
+// Hook is a command that is run for an event.
+type Hook struct {
+	// Event is HookAdded, HookFailed, HookTrashed or HookDeleted.
+	Event string `json:"event"`
+	// Command is the program and its arguments.  It is not run by a
+	// shell.
+	Command []string `json:"command"`
+	// Timeout after which the command is killed.
+	Timeout time.Duration `json:"-"`
+	// Mailboxes restricts the hook to the voicemails in these
+	// mailboxes or for these called numbers.
+	Mailboxes []string `json:"mailboxes"`
+}
+
+// UnmarshalJSON reads the timeout as a duration string, e.g. "30s".
+func (h *Hook) UnmarshalJSON(data []byte) error {
+	type hook Hook
+	var v struct {
+		hook
+		Timeout string `json:"timeout"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*h = Hook(v.hook)
+	if v.Timeout != "" {
+		var err error
+		if h.Timeout, err = time.ParseDuration(v.Timeout); err != nil {
+			return fmt.Errorf("timeout: %v", err)
+		}
+	}
+	return nil
+}
+
+// HookConfig lists the hooks and how many may run at the same time.
+type HookConfig struct {
+	Concurrency int    `json:"concurrency"`
+	Hooks       []Hook `json:"hooks"`
+}
+
+const (
+	defaultHookTimeout     = time.Minute
+	defaultHookConcurrency = 4
+	// maxHookOutput is the number of bytes of a hook's output that
+	// are kept and logged.
+	maxHookOutput = 4096
+	// hookWaitDelay is how long the output of a killed hook is read
+	// before it is closed.
+	hookWaitDelay = time.Second
+)
+
+// limitedBuffer keeps the first limit bytes written to it and discards
+// the rest.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if n := b.limit - b.Len(); len(p) > n {
+		b.Buffer.Write(p[:n])
+		b.truncated = true
+	} else {
+		b.Buffer.Write(p)
+	}
+	return len(p), nil
+}
+
+// LoadHookConfig reads hook definitions from a JSON file.
+func LoadHookConfig(file string) (HookConfig, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return HookConfig{}, err
+	}
+
+	config, err := ParseHookConfig(data)
+	if err != nil {
+		return HookConfig{}, fmt.Errorf("%s: %v", file, err)
+	}
+	return config, nil
+}
+
+// ParseHookConfig reads hook definitions from JSON.
+func ParseHookConfig(data []byte) (HookConfig, error) {
+	var config HookConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return HookConfig{}, err
+	}
+
+	for _, hook := range config.Hooks {
+		switch hook.Event {
+		case HookAdded, HookFailed, HookTrashed, HookDeleted:
+		default:
+			return HookConfig{}, fmt.Errorf("unknown hook event %q", hook.Event)
+		}
+		if len(hook.Command) == 0 || hook.Command[0] == "" {
+			return HookConfig{}, fmt.Errorf("hook without command")
+		}
+	}
+
+	return config, nil
+}
+
+// hookInput is written as JSON to the standard input of hooks.
+type hookInput struct {
+	Notification
+	// File is the absolute path of the recording.  It does not exist
+	// anymore for deleted voicemails.
+	File  string `json:"file,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// StartHooks runs the hook commands for voicemail events until the
+// returned function is called.  That function waits for the running
+// hooks; hooks still waiting for their turn are skipped.
+func StartHooks(db model.Database, config HookConfig, mailboxes model.Mailboxes, voicemailDir string, publicURL string) func() {
+	if len(config.Hooks) == 0 {
+		return func() {}
+	}
+	if config.Concurrency < 1 {
+		config.Concurrency = defaultHookConcurrency
+	}
+	running := make(chan struct{}, config.Concurrency)
+	if dir, err := filepath.Abs(voicemailDir); err == nil {
+		voicemailDir = dir
+	}
+
+	events, unsubscribe := db.Subscribe()
+	d := &deliveries{stop: make(chan struct{})}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for event := range events {
+			name, ok := hookEvent(event)
+			if !ok {
+				continue
+			}
+
+			input := hookInput{
+				Notification: newNotification(event.Voicemail, mailboxes, publicURL),
+				Error:        event.Error,
+			}
+			input.Event = "voicemail." + name
+			if event.Type == model.VoicemailFailed {
+				input.AudioURL = ""
+			} else {
+				input.File = path.Join(voicemailDir, path.Base(event.Voicemail.VoicemailPath))
+			}
+
+			for _, hook := range config.Hooks {
+				if hook.Event == name && grants(mailboxes, hook.Mailboxes, event.Voicemail.Called) {
+					hook := hook
+					d.start(func() {
+						select {
+						case running <- struct{}{}:
+						case <-d.stop:
+							logger.Warn("Hook skipped, hooks stopped", "hook", strings.Join(hook.Command, " "), "voicemail", input.Id)
+							return
+						}
+						defer func() { <-running }()
+						runHook(db, hook, input)
+					})
+				}
+			}
+		}
+	}()
+	return func() {
+		unsubscribe()
+		<-done
+		close(d.stop)
+		d.wg.Wait()
+	}
+}
+
+// hookEnvironment returns the metadata of a voicemail as environment
+// variables.
+func hookEnvironment(input hookInput) []string {
+	return []string{
+		"VOICEMAIL_EVENT=" + input.Event,
+		"VOICEMAIL_ID=" + strconv.Itoa(input.Id),
+		"VOICEMAIL_CALLER=" + input.Caller,
+		"VOICEMAIL_CALLED=" + input.Called,
+		"VOICEMAIL_MAILBOX=" + input.Mailbox,
+		"VOICEMAIL_DATE=" + input.Date.Format(time.RFC3339),
+		"VOICEMAIL_DURATION=" + strconv.FormatFloat(input.Duration, 'f', -1, 64),
+		"VOICEMAIL_FILE=" + input.File,
+		"VOICEMAIL_AUDIO_URL=" + input.AudioURL,
+		"VOICEMAIL_ERROR=" + input.Error,
+	}
+}
+
+// runHook runs a hook command, logs its output and records the result
+// in the delivery log.
+func runHook(db model.Database, hook Hook, input hookInput) {
+	stdin, err := json.Marshal(input)
+	if err != nil {
+		logger.Error("Unable to encode hook input", "voicemail", input.Id, "error", err)
+		return
+	}
+
+	timeout := hook.Timeout
+	if timeout <= 0 {
+		timeout = defaultHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...)
+	cmd.Env = append(os.Environ(), hookEnvironment(input)...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	output := &limitedBuffer{limit: maxHookOutput}
+	cmd.Stdout = output
+	cmd.Stderr = output
+	// The hook runs in its own process group, so that the processes it
+	// started are killed with it on timeout.  Otherwise they keep the
+	// output open and Run waits for them.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = hookWaitDelay
+
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %v", timeout)
+	}
+
+	out := output.String()
+	if output.truncated {
+		out += "..."
+	}
+	out = strings.TrimSpace(out)
+
+	command := strings.Join(hook.Command, " ")
+	log := logger.With("hook", command, "event", input.Event, "voicemail", input.Id)
+	if err != nil {
+		log.Warn("Hook failed", "error", err)
+	} else {
+		log.Info("Hook finished")
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if line != "" {
+			log.Info("Hook output", "line", line)
+		}
+	}
+
+	delivery := model.Delivery{
+		Voicemail: input.Id,
+		Channel:   "exec",
+		Target:    command,
+		Attempt:   1,
+		Success:   err == nil,
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+	if err := db.LogDelivery(delivery); err != nil {
+		logger.Error("Unable to log delivery", "error", err)
+	}
+}
@@ -0,0 +1,165 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"../model"
+)
+
+func TestHooks(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	script := path.Join(tempDir, "hook.sh")
+	err := ioutil.WriteFile(script, []byte(`#!/bin/sh
+echo "$VOICEMAIL_EVENT $VOICEMAIL_ID $VOICEMAIL_CALLER $VOICEMAIL_MAILBOX $VOICEMAIL_ERROR" > "$1.env"
+cat > "$1.json"
+echo "output of $1"
+`), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mailboxes := model.Mailboxes{{Name: "home", Numbers: []string{"111"}}}
+	out := func(name string) string { return path.Join(tempDir, name) }
+	StartHooks(db, HookConfig{
+		Concurrency: 1,
+		Hooks: []Hook{
+			{Event: HookAdded, Command: []string{script, out("added")}},
+			{Event: HookAdded, Command: []string{script, out("other")}, Mailboxes: []string{"222"}},
+			{Event: HookFailed, Command: []string{script, out("failed")}},
+			{Event: HookTrashed, Command: []string{script, out("trashed")}},
+			{Event: HookDeleted, Command: []string{script, out("deleted")}},
+			{Event: HookDeleted, Command: []string{"sleep", "5"}, Timeout: 50 * time.Millisecond},
+			// The shell's child keeps the output open.
+			{Event: HookDeleted, Command: []string{"sh", "-c", "sleep 5; echo late"}, Timeout: 50 * time.Millisecond},
+		},
+	}, mailboxes, tempDir, "")
+
+	err = db.AddVoicemail(model.Voicemail{Caller: "0301234567", Called: "111", Date: time.Now()}, []byte("audio"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.ReportFailure(model.Voicemail{Caller: "0307654321"}, errors.New("lame not found"))
+
+	var deliveries []model.Delivery
+	wait := func(n int) {
+		for i := 0; i < 200 && len(deliveries) < n; i++ {
+			time.Sleep(10 * time.Millisecond)
+			if deliveries, err = db.GetDeliveries(-1); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if len(deliveries) != n {
+			t.Fatalf("Expected %d deliveries, got %+v", n, deliveries)
+		}
+	}
+	wait(2)
+
+	// Only moving into the trash runs the hook, not trashing again.
+	for i := 0; i < 2; i++ {
+		if err := db.DeleteVoicemail(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wait(3)
+
+	if err := db.PurgeVoicemail(1); err != nil {
+		t.Fatal(err)
+	}
+	wait(6)
+
+	tests := map[string]string{
+		"added":   "voicemail.added 1 0301234567 home \n",
+		"failed":  "voicemail.failed 0 0307654321  lame not found\n",
+		"trashed": "voicemail.trashed 1 0301234567 home \n",
+		"deleted": "voicemail.deleted 1 0301234567 home \n",
+	}
+	for name, expected := range tests {
+		env, err := ioutil.ReadFile(out(name) + ".env")
+		if err != nil || string(env) != expected {
+			t.Errorf("%s: expected environment %q, got %q, %v", name, expected, env, err)
+		}
+	}
+	if _, err := os.Stat(out("other") + ".env"); !os.IsNotExist(err) {
+		t.Errorf("Hook for other mailbox was run: %v", err)
+	}
+
+	var input hookInput
+	data, _ := ioutil.ReadFile(out("added") + ".json")
+	if err := json.Unmarshal(data, &input); err != nil {
+		t.Fatal(err)
+	}
+	if input.Caller != "0301234567" || !strings.HasPrefix(input.File, tempDir+"/") || input.Mailbox != "home" {
+		t.Errorf("Unexpected input: %s", data)
+	}
+
+	timedOut := 0
+	for _, delivery := range deliveries {
+		if strings.Contains(delivery.Target, "sleep 5") {
+			if !delivery.Success && strings.Contains(delivery.Error, "timed out") {
+				timedOut++
+			}
+		} else if !delivery.Success || delivery.Channel != "exec" {
+			t.Errorf("Unexpected delivery: %+v", delivery)
+		}
+	}
+	if timedOut != 2 {
+		t.Errorf("Hooks did not time out: %+v", deliveries)
+	}
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	b := &limitedBuffer{limit: 5}
+	for _, s := range []string{"abc", "def", "ghi"} {
+		if n, err := b.Write([]byte(s)); n != len(s) || err != nil {
+			t.Errorf("Write returned %d, %v", n, err)
+		}
+	}
+	if b.String() != "abcde" || !b.truncated {
+		t.Errorf("Unexpected buffer %q, truncated %v", b.String(), b.truncated)
+	}
+}
+
+func TestHooksStop(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	out := func(name string) string { return path.Join(tempDir, name) }
+	stop := StartHooks(db, HookConfig{
+		Concurrency: 1,
+		Hooks: []Hook{
+			{Event: HookAdded, Command: []string{"sh", "-c", "sleep 0.3; touch " + out("first")}},
+			{Event: HookAdded, Command: []string{"sh", "-c", "sleep 0.3; touch " + out("second")}},
+		},
+	}, nil, tempDir, "")
+
+	err := db.AddVoicemail(model.Voicemail{Caller: "0301234567", Called: "111", Date: time.Now()}, []byte("audio"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	stop()
+
+	// The running hook is finished and logged, the waiting one skipped.
+	run := 0
+	for _, name := range []string{"first", "second"} {
+		if _, err := os.Stat(out(name)); err == nil {
+			run++
+		}
+	}
+	deliveries, err := db.GetDeliveries(-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run != 1 || len(deliveries) != 1 || !deliveries[0].Success {
+		t.Errorf("Expected one finished hook, got %d: %+v", run, deliveries)
+	}
+}
@@ -0,0 +1,439 @@
+package notify
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"../model"
+)
+
+// MQTTConfig describes the broker that voicemail events are published
+// to.
+type MQTTConfig struct {
+	// Broker is the host:port of the MQTT broker.
+	Broker   string `json:"broker"`
+	TLS      bool   `json:"tls"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	ClientID string `json:"client_id"`
+	// TopicPrefix is prepended to all topics, "voicemail" by default.
+	TopicPrefix string `json:"topic_prefix"`
+}
+
+// LoadMQTTConfig reads the MQTT configuration from a JSON file.
+func LoadMQTTConfig(file string) (MQTTConfig, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return MQTTConfig{}, err
+	}
+
+	config, err := ParseMQTTConfig(data)
+	if err != nil {
+		return MQTTConfig{}, fmt.Errorf("%s: %v", file, err)
+	}
+	return config, nil
+}
+
+// ParseMQTTConfig reads the MQTT configuration from JSON.
+func ParseMQTTConfig(data []byte) (MQTTConfig, error) {
+	var config MQTTConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return MQTTConfig{}, err
+	}
+
+	if _, _, err := net.SplitHostPort(config.Broker); err != nil {
+		return MQTTConfig{}, fmt.Errorf("invalid broker %q, expected host:port", config.Broker)
+	}
+	if strings.ContainsAny(config.TopicPrefix, "#+") {
+		return MQTTConfig{}, fmt.Errorf("invalid topic prefix %q", config.TopicPrefix)
+	}
+
+	return config, nil
+}
+
+// MQTT timing.  The reconnect delay doubles after every failed attempt
+// up to mqttMaxReconnect.
+var (
+	mqttKeepAlive    = 60 * time.Second
+	mqttTimeout      = 10 * time.Second
+	mqttReconnect    = time.Second
+	mqttMaxReconnect = time.Minute
+)
+
+// mqttMaxPending is the number of notifications about new voicemails
+// that are kept while the broker is unreachable.
+const mqttMaxPending = 100
+
+// StartMQTT publishes voicemail events to an MQTT broker:
+//
+//	prefix/unread          number of unheard voicemails in the inbox (retained)
+//	prefix/MAILBOX/unread  the same for each mailbox (retained)
+//	prefix/new             a Notification for every new voicemail
+//
+// The connection is reestablished if it fails.  The returned function
+// disconnects from the broker and waits until it is done.
+func StartMQTT(db model.Database, config MQTTConfig, mailboxes model.Mailboxes, publicURL string) func() {
+	if config.Broker == "" {
+		return func() {}
+	}
+	if config.TopicPrefix == "" {
+		config.TopicPrefix = "voicemail"
+	}
+	if config.ClientID == "" {
+		hostname, _ := os.Hostname()
+		config.ClientID = "voicemail-" + hostname
+	}
+
+	events, unsubscribe := db.Subscribe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runMQTT(db, config, mailboxes, publicURL, events)
+	}()
+	return func() {
+		unsubscribe()
+		<-done
+	}
+}
+
+func runMQTT(db model.Database, config MQTTConfig, mailboxes model.Mailboxes, publicURL string, events <-chan model.Event) {
+	var client *mqttClient
+	var closed <-chan struct{}
+	pending := []Notification{}
+	countsChanged := true
+	reconnectDelay := mqttReconnect
+	reconnect := time.After(0)
+	keepAlive := time.NewTicker(mqttKeepAlive / 2)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				if client != nil {
+					client.close()
+				}
+				return
+			}
+			if event.Type == model.VoicemailAdded {
+				if len(pending) == mqttMaxPending {
+					pending = pending[1:]
+				}
+				pending = append(pending, newNotification(event.Voicemail, mailboxes, publicURL))
+			}
+			switch event.Type {
+			case model.VoicemailAdded, model.VoicemailChanged, model.VoicemailPurged:
+				countsChanged = true
+			}
+
+		case <-reconnect:
+			reconnect = nil
+			var err error
+			if client, err = dialMQTT(config); err != nil {
+				logger.Warn("Unable to connect to MQTT broker", "broker", config.Broker, "error", err)
+				client = nil
+				reconnect = time.After(reconnectDelay)
+				if reconnectDelay *= 2; reconnectDelay > mqttMaxReconnect {
+					reconnectDelay = mqttMaxReconnect
+				}
+				continue
+			}
+			logger.Info("Connected to MQTT broker", "broker", config.Broker)
+			closed = client.closed
+			reconnectDelay = mqttReconnect
+			countsChanged = true
+
+		case <-keepAlive.C:
+			if client != nil {
+				if !client.alive() {
+					logger.Warn("MQTT broker does not answer pings", "broker", config.Broker)
+					client.close()
+				} else if err := client.ping(); err != nil {
+					client.close()
+				}
+			}
+
+		case <-closed:
+			logger.Warn("Connection to MQTT broker lost", "broker", config.Broker, "error", client.err)
+			client, closed = nil, nil
+			reconnect = time.After(reconnectDelay)
+		}
+
+		if client == nil {
+			continue
+		}
+
+		for len(pending) > 0 {
+			payload, err := json.Marshal(pending[0])
+			if err == nil {
+				err = client.publish(config.TopicPrefix+"/new", payload, false)
+			}
+			if err != nil {
+				client.close()
+				break
+			}
+			pending = pending[1:]
+		}
+
+		if countsChanged {
+			if err := publishUnreadCounts(db, client, config, mailboxes); err != nil {
+				client.close()
+			} else {
+				countsChanged = false
+			}
+		}
+	}
+}
+
+// mqttRetained holds the unread count topics published to each broker,
+// so that the topics of mailboxes removed by a reload are cleared.
+var mqttRetained = struct {
+	sync.Mutex
+	topics map[string]map[string]bool
+}{topics: map[string]map[string]bool{}}
+
+func publishUnreadCounts(db model.Database, client *mqttClient, config MQTTConfig, mailboxes model.Mailboxes) error {
+	prefix := config.TopicPrefix
+	counts := map[string]int{}
+
+	n, err := db.Count(model.Inbox, model.Filter{Read: model.OnlyUnread})
+	if err != nil {
+		logger.Error("Unable to count unread voicemails", "error", err)
+		return nil
+	}
+	counts[prefix+"/unread"] = n
+
+	for _, mailbox := range mailboxes {
+		filter := mailboxes.Filter(mailbox)
+		filter.Read = model.OnlyUnread
+		if n, err = db.Count(model.Inbox, filter); err != nil {
+			logger.Error("Unable to count unread voicemails", "error", err)
+			return nil
+		}
+		counts[prefix+"/"+mailbox.Name+"/unread"] = n
+	}
+
+	for topic, n := range counts {
+		if err := client.publish(topic, []byte(strconv.Itoa(n)), true); err != nil {
+			return err
+		}
+	}
+
+	mqttRetained.Lock()
+	defer mqttRetained.Unlock()
+	for topic := range mqttRetained.topics[config.Broker] {
+		if _, ok := counts[topic]; !ok {
+			// An empty retained message deletes the topic.
+			if err := client.publish(topic, nil, true); err != nil {
+				return err
+			}
+		}
+	}
+	topics := map[string]bool{}
+	for topic := range counts {
+		topics[topic] = true
+	}
+	mqttRetained.topics[config.Broker] = topics
+	return nil
+}
+
+// mqttClient is a minimal MQTT 3.1.1 client that publishes messages
+// with QoS 0.
+type mqttClient struct {
+	conn net.Conn
+	// closed is closed when the connection fails, err tells why.
+	closed chan struct{}
+	err    error
+	// pingresp is the time of the last PINGRESP, or of the connect, in
+	// Unix nanoseconds.
+	pingresp int64
+}
+
+// MQTT control packet types.
+const (
+	mqttConnect    = 1 << 4
+	mqttConnack    = 2 << 4
+	mqttPublish    = 3 << 4
+	mqttPingreq    = 12 << 4
+	mqttPingresp   = 13 << 4
+	mqttDisconnect = 14 << 4
+)
+
+var mqttConnackErrors = map[byte]string{
+	1: "unacceptable protocol version",
+	2: "identifier rejected",
+	3: "server unavailable",
+	4: "bad user name or password",
+	5: "not authorized",
+}
+
+func dialMQTT(config MQTTConfig) (*mqttClient, error) {
+	dialer := &net.Dialer{Timeout: mqttTimeout}
+	var conn net.Conn
+	var err error
+	if config.TLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", config.Broker, nil)
+	} else {
+		conn, err = dialer.Dial("tcp", config.Broker)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	flags := byte(0x02) // clean session
+	payload := mqttString(config.ClientID)
+	if config.Username != "" {
+		flags |= 0x80
+		payload = append(payload, mqttString(config.Username)...)
+		if config.Password != "" {
+			flags |= 0x40
+			payload = append(payload, mqttString(config.Password)...)
+		}
+	}
+	keepAlive := int(mqttKeepAlive / time.Second)
+	body := append(mqttString("MQTT"), 4, flags, byte(keepAlive>>8), byte(keepAlive))
+	body = append(body, payload...)
+
+	conn.SetDeadline(time.Now().Add(mqttTimeout))
+	if _, err := conn.Write(mqttPacket(mqttConnect, body)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	r := bufio.NewReader(conn)
+	packetType, body, err := readMQTTPacket(r)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if packetType != mqttConnack || len(body) != 2 {
+		conn.Close()
+		return nil, errors.New("unexpected response to CONNECT")
+	}
+	if body[1] != 0 {
+		conn.Close()
+		if message, ok := mqttConnackErrors[body[1]]; ok {
+			return nil, errors.New(message)
+		}
+		return nil, fmt.Errorf("connection refused with code %d", body[1])
+	}
+	conn.SetDeadline(time.Time{})
+
+	client := &mqttClient{conn: conn, closed: make(chan struct{}), pingresp: time.Now().UnixNano()}
+	go client.read(r)
+	return client, nil
+}
+
+// read records PINGRESPs and discards the other packets from the broker
+// until the connection fails.
+func (c *mqttClient) read(r *bufio.Reader) {
+	for {
+		packetType, _, err := readMQTTPacket(r)
+		if err != nil {
+			c.err = err
+			c.conn.Close()
+			close(c.closed)
+			return
+		}
+		if packetType == mqttPingresp {
+			atomic.StoreInt64(&c.pingresp, time.Now().UnixNano())
+		}
+	}
+}
+
+// alive reports whether the broker answered a ping within the keep alive
+// period.
+func (c *mqttClient) alive() bool {
+	return time.Since(time.Unix(0, atomic.LoadInt64(&c.pingresp))) < mqttKeepAlive
+}
+
+func (c *mqttClient) write(packet []byte) error {
+	c.conn.SetWriteDeadline(time.Now().Add(mqttTimeout))
+	_, err := c.conn.Write(packet)
+	return err
+}
+
+func (c *mqttClient) publish(topic string, payload []byte, retain bool) error {
+	flags := byte(0)
+	if retain {
+		flags = 0x01
+	}
+	return c.write(mqttPacket(mqttPublish|flags, append(mqttString(topic), payload...)))
+}
+
+func (c *mqttClient) ping() error {
+	return c.write(mqttPacket(mqttPingreq, nil))
+}
+
+// close ends the connection.  The reading goroutine notices and closes
+// c.closed.
+func (c *mqttClient) close() {
+	c.write(mqttPacket(mqttDisconnect, nil))
+	c.conn.Close()
+}
+
+// mqttString encodes a length-prefixed UTF-8 string.
+func mqttString(s string) []byte {
+	return append([]byte{byte(len(s) >> 8), byte(len(s))}, s...)
+}
+
+// mqttPacket adds the fixed header with the remaining length to body.
+func mqttPacket(header byte, body []byte) []byte {
+	packet := []byte{header}
+	n := len(body)
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		packet = append(packet, b)
+		if n == 0 {
+			break
+		}
+	}
+	return append(packet, body...)
+}
+
+// readMQTTPacket returns the type and flags and the body of the next
+// packet.
+func readMQTTPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		if i == 3 && b&0x80 != 0 {
+			return 0, nil, errors.New("malformed remaining length")
+		}
+		length += int(b&0x7f) * multiplier
+		multiplier *= 128
+		if b&0x80 == 0 {
+			break
+		}
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
@@ -0,0 +1,228 @@
+package notify
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"../model"
+)
+
+type mqttMessage struct {
+	topic   string
+	payload string
+	retain  bool
+}
+
+// fakeBroker accepts MQTT connections and sends the client ids to
+// connects and the published messages to messages.  PINGREQs are only
+// answered if pong is set.  The returned function drops all connections.
+func fakeBroker(t *testing.T, messages chan<- mqttMessage, connects chan<- string, pong bool) (net.Listener, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mutex sync.Mutex
+	conns := []net.Conn{}
+	drop := func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		for _, conn := range conns {
+			conn.Close()
+		}
+		conns = nil
+	}
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			mutex.Lock()
+			conns = append(conns, conn)
+			mutex.Unlock()
+
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+
+				packetType, body, err := readMQTTPacket(r)
+				if err != nil || packetType != mqttConnect || string(body[2:6]) != "MQTT" {
+					t.Errorf("Expected CONNECT, got %x %q, %v", packetType, body, err)
+					return
+				}
+				// Skip protocol name, level, flags and keep alive.
+				connects <- string(body[12 : 12+int(body[10])<<8+int(body[11])])
+				conn.Write([]byte{mqttConnack, 2, 0, 0})
+
+				for {
+					packetType, body, err := readMQTTPacket(r)
+					if err != nil || packetType&0xf0 == mqttDisconnect {
+						return
+					}
+					switch packetType & 0xf0 {
+					case mqttPublish:
+						n := int(body[0])<<8 + int(body[1])
+						messages <- mqttMessage{string(body[2 : 2+n]), string(body[2+n:]), packetType&0x01 != 0}
+					case mqttPingreq:
+						if pong {
+							conn.Write([]byte{mqttPingresp, 0})
+						}
+					}
+				}
+			}()
+		}
+	}()
+
+	return l, drop
+}
+
+func TestMQTT(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	mqttReconnect = 10 * time.Millisecond
+
+	messages := make(chan mqttMessage, 100)
+	connects := make(chan string, 10)
+	l, drop := fakeBroker(t, messages, connects, true)
+	defer l.Close()
+
+	mailboxes := model.Mailboxes{
+		{Name: "home", Numbers: []string{"111"}},
+		{Name: "office"},
+	}
+	stop := StartMQTT(db, MQTTConfig{Broker: l.Addr().String(), ClientID: "test", TopicPrefix: "vm"}, mailboxes, "")
+
+	// expect reads messages until the retained topics have the
+	// expected values and returns the other messages.
+	expect := func(retained map[string]string) []mqttMessage {
+		others := []mqttMessage{}
+		current := map[string]string{}
+		for {
+			match := true
+			for topic, payload := range retained {
+				if current[topic] != payload {
+					match = false
+				}
+			}
+			if match {
+				return others
+			}
+
+			select {
+			case message := <-messages:
+				if message.retain {
+					current[message.topic] = message.payload
+				} else {
+					others = append(others, message)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatalf("Expected %v, got %v", retained, current)
+			}
+		}
+	}
+
+	if id := <-connects; id != "test" {
+		t.Errorf("Unexpected client id %q", id)
+	}
+	expect(map[string]string{"vm/unread": "0", "vm/home/unread": "0", "vm/office/unread": "0"})
+
+	for _, called := range []string{"111", "222", "333"} {
+		err := db.AddVoicemail(model.Voicemail{Caller: "0301234567", Called: called, Date: time.Now()}, []byte("audio"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	others := expect(map[string]string{"vm/unread": "3", "vm/home/unread": "1", "vm/office/unread": "2"})
+	for len(others) < 3 {
+		select {
+		case message := <-messages:
+			if !message.retain {
+				others = append(others, message)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Expected 3 notifications, got %v", others)
+		}
+	}
+	var notification Notification
+	if err := json.Unmarshal([]byte(others[2].payload), &notification); err != nil {
+		t.Fatal(err)
+	}
+	if others[2].topic != "vm/new" || notification.Called != "333" || notification.Mailbox != "office" {
+		t.Errorf("Unexpected notification %v", others[2])
+	}
+
+	// Changes while disconnected are published after reconnecting.
+	drop()
+	if err := db.MarkRead(1); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-connects:
+	case <-time.After(2 * time.Second):
+		t.Fatal("No reconnect")
+	}
+	expect(map[string]string{"vm/unread": "2", "vm/home/unread": "0"})
+
+	// Nothing is published after stopping.
+	stop()
+	time.Sleep(50 * time.Millisecond)
+	for len(messages) > 0 {
+		<-messages
+	}
+	if err := db.MarkRead(2); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case message := <-messages:
+		t.Errorf("Unexpected message after stop: %v", message)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// The unread count of a mailbox removed by a reload is cleared.
+	stop = StartMQTT(db, MQTTConfig{Broker: l.Addr().String(), ClientID: "test", TopicPrefix: "vm"}, mailboxes[:1], "")
+	defer stop()
+	<-connects
+	for cleared := false; !cleared; {
+		select {
+		case message := <-messages:
+			cleared = message.topic == "vm/office/unread" && message.payload == "" && message.retain
+		case <-time.After(2 * time.Second):
+			t.Fatal("Unread count of removed mailbox not cleared")
+		}
+	}
+}
+
+func TestMQTTKeepAlive(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	mqttReconnect = 10 * time.Millisecond
+	defer func(keepAlive time.Duration) { mqttKeepAlive = keepAlive }(mqttKeepAlive)
+	mqttKeepAlive = 100 * time.Millisecond
+
+	messages := make(chan mqttMessage, 100)
+	connects := make(chan string, 10)
+	l, _ := fakeBroker(t, messages, connects, false)
+	defer l.Close()
+
+	stop := StartMQTT(db, MQTTConfig{Broker: l.Addr().String(), ClientID: "test", TopicPrefix: "vm"}, nil, "")
+	defer stop()
+
+	// The connection is dropped and reestablished if the broker does
+	// not answer pings.
+	for i := 0; i < 2; i++ {
+		select {
+		case <-connects:
+		case <-time.After(2 * time.Second):
+			t.Fatal("No reconnect without PINGRESP")
+		}
+	}
+}
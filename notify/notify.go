@@ -0,0 +1,140 @@
+// Package notify informs external systems about new voicemails.
+package notify
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"../model"
+	"../utils"
+)
+
+var logger = utils.Logger("notify")
+
+// Notification describes a new voicemail.  It is sent as JSON.
+type Notification struct {
+	Event   string    `json:"event"`
+	Id      int       `json:"id"`
+	Caller  string    `json:"caller"`
+	Called  string    `json:"called"`
+	Mailbox string    `json:"mailbox,omitempty"`
+	Date    time.Time `json:"date"`
+	// Duration is given in seconds.
+	Duration float64 `json:"duration"`
+	// AudioURL points to the recording in the API.  It is only
+	// absolute if a public URL is configured.
+	AudioURL string `json:"audio_url"`
+}
+
+// EventAdded is the event of notifications about new voicemails.
+const EventAdded = "voicemail.added"
+
+func newNotification(voicemail model.Voicemail, mailboxes model.Mailboxes, publicURL string) Notification {
+	mailbox, _ := mailboxes.ForNumber(voicemail.Called)
+	return Notification{
+		Event:    EventAdded,
+		Id:       voicemail.Id,
+		Caller:   voicemail.Caller,
+		Called:   voicemail.Called,
+		Mailbox:  mailbox.Name,
+		Date:     voicemail.Date,
+		Duration: voicemail.Duration.Seconds(),
+		AudioURL: strings.TrimRight(publicURL, "/") +
+			"/api/v1/voicemails/" + strconv.Itoa(voicemail.Id) + "/audio",
+	}
+}
+
+// grants returns whether a target restricted to mailboxes receives
+// notifications about voicemails for the called number.  Targets
+// without mailboxes receive all notifications.
+func grants(mailboxes model.Mailboxes, restriction []string, called string) bool {
+	return len(restriction) == 0 || mailboxes.Permissions(restriction).Allows(called)
+}
+
+// Retries of failed deliveries.  The delay doubles after each attempt.
+var (
+	retryAttempts = 5
+	retryBackoff  = 10 * time.Second
+)
+
+// deliveries tracks the deliveries started for new voicemails, so that
+// stopping a notifier can wait for them.  Closing stop ends the waits
+// between retries.
+type deliveries struct {
+	wg   sync.WaitGroup
+	stop chan struct{}
+}
+
+// start runs f in the background.
+func (d *deliveries) start(f func()) {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		f()
+	}()
+}
+
+// deliver calls send until it succeeds, reports that retrying is
+// pointless, all attempts failed or stop is closed.  Every attempt is
+// logged in the database.
+func deliver(db model.Database, stop <-chan struct{}, channel string, target string, voicemail int, send func() (retry bool, err error)) {
+	delay := retryBackoff
+	for attempt := 1; attempt <= retryAttempts; attempt++ {
+		retry, err := send()
+
+		delivery := model.Delivery{
+			Voicemail: voicemail,
+			Channel:   channel,
+			Target:    target,
+			Attempt:   attempt,
+			Success:   err == nil,
+		}
+		if err != nil {
+			delivery.Error = err.Error()
+			logger.Warn("Delivery failed", "channel", channel, "target", target,
+				"voicemail", voicemail, "attempt", attempt, "error", err)
+		}
+		if err := db.LogDelivery(delivery); err != nil {
+			logger.Error("Unable to log delivery", "error", err)
+		}
+
+		if err == nil || !retry {
+			return
+		}
+		if attempt < retryAttempts {
+			select {
+			case <-stop:
+				logger.Warn("Delivery given up, notifications stopped", "channel", channel,
+					"target", target, "voicemail", voicemail)
+				return
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+	}
+}
+
+// subscribe calls f for every new voicemail until the returned function
+// is called.  That function waits for the deliveries that f started;
+// their pending retries are given up.
+func subscribe(db model.Database, f func(model.Voicemail, *deliveries)) func() {
+	events, unsubscribe := db.Subscribe()
+	d := &deliveries{stop: make(chan struct{})}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for event := range events {
+			if event.Type == model.VoicemailAdded {
+				f(event.Voicemail, d)
+			}
+		}
+	}()
+	return func() {
+		unsubscribe()
+		<-done
+		close(d.stop)
+		d.wg.Wait()
+	}
+}
@@ -0,0 +1,150 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"../model"
+)
+
+// Webhook is an HTTP endpoint that receives a Notification for every
+// new voicemail as a POST request.
+type Webhook struct {
+	URL string `json:"url"`
+	// Secret signs the request body.  The signature is sent in the
+	// X-Voicemail-Signature header as "sha256=" followed by the hex
+	// encoded HMAC-SHA256 of the body.
+	Secret string `json:"secret"`
+	// Mailboxes restricts the webhook to the voicemails in these
+	// mailboxes or for these called numbers.
+	Mailboxes []string `json:"mailboxes"`
+}
+
+var webhookClient = &http.Client{Timeout: 30 * time.Second}
+
+// LoadWebhooks reads webhook definitions from a JSON file containing a
+// list of webhooks.
+func LoadWebhooks(file string) ([]Webhook, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks, err := ParseWebhooks(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return webhooks, nil
+}
+
+// ParseWebhooks reads webhook definitions from a JSON list.
+func ParseWebhooks(data []byte) ([]Webhook, error) {
+	var webhooks []Webhook
+	if err := json.Unmarshal(data, &webhooks); err != nil {
+		return nil, err
+	}
+
+	for _, webhook := range webhooks {
+		u, err := url.Parse(webhook.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid webhook URL %q", webhook.URL)
+		}
+	}
+
+	return webhooks, nil
+}
+
+// Sign returns the signature of a request body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// StartWebhooks sends a notification to the webhooks for every new
+// voicemail.  The returned function stops sending notifications and
+// waits for the deliveries in progress.  Their retries are given up.
+func StartWebhooks(db model.Database, webhooks []Webhook, mailboxes model.Mailboxes, publicURL string) func() {
+	if len(webhooks) == 0 {
+		return func() {}
+	}
+
+	return subscribe(db, func(voicemail model.Voicemail, d *deliveries) {
+		notification := newNotification(voicemail, mailboxes, publicURL)
+		for _, webhook := range webhooks {
+			if grants(mailboxes, webhook.Mailboxes, voicemail.Called) {
+				webhook := webhook
+				d.start(func() { deliverWebhook(db, d.stop, webhook, notification) })
+			}
+		}
+	})
+}
+
+// deliverWebhook posts a notification and retries until the webhook
+// accepts it or rejects it with a client error.
+func deliverWebhook(db model.Database, stop <-chan struct{}, webhook Webhook, notification Notification) {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		logger.Error("Unable to encode notification", "voicemail", notification.Id, "error", err)
+		return
+	}
+	id, err := deliveryId()
+	if err != nil {
+		logger.Error("Unable to create delivery id", "error", err)
+		return
+	}
+
+	deliver(db, stop, "webhook", webhook.URL, notification.Id, func() (bool, error) {
+		return postWebhook(webhook, id, body)
+	})
+}
+
+// postWebhook sends one request and reports whether a failed request
+// should be retried.
+func postWebhook(webhook Webhook, id string, body []byte) (bool, error) {
+	req, err := http.NewRequest("POST", webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "voicemail")
+	req.Header.Set("X-Voicemail-Event", EventAdded)
+	req.Header.Set("X-Voicemail-Delivery", id)
+	if webhook.Secret != "" {
+		req.Header.Set("X-Voicemail-Signature", Sign(webhook.Secret, body))
+	}
+
+	res, err := webhookClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	io.Copy(ioutil.Discard, io.LimitReader(res.Body, 64*1024))
+	res.Body.Close()
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return false, nil
+	}
+	retry := res.StatusCode >= 500 || res.StatusCode == http.StatusTooManyRequests ||
+		res.StatusCode == http.StatusRequestTimeout
+	return retry, fmt.Errorf("unexpected status %s", res.Status)
+}
+
+// deliveryId identifies a notification across retries, so that
+// receivers can ignore duplicates.
+func deliveryId() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
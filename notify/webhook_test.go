@@ -0,0 +1,165 @@
+package notify
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"../model"
+)
+
+func openTestDatabase(t *testing.T) (model.Database, string) {
+	tempDir, err := ioutil.TempDir("", "voicemail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return model.OpenDatabase(path.Join(tempDir, "voicemail.sqlite"), tempDir), tempDir
+}
+
+type request struct {
+	header http.Header
+	body   []byte
+}
+
+func TestWebhooks(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	retryBackoff = 10 * time.Millisecond
+
+	requests := make(chan request, 10)
+	failures := 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests <- request{r.Header, body}
+		if failures > 0 {
+			failures--
+			http.Error(w, "try again", http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	mailboxes := model.Mailboxes{
+		{Name: "home", Numbers: []string{"111"}},
+		{Name: "office", Numbers: []string{"222"}},
+	}
+	StartWebhooks(db, []Webhook{{URL: server.URL, Secret: "secret", Mailboxes: []string{"office"}}},
+		mailboxes, "https://voicemail.example.com/")
+
+	date := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	for _, called := range []string{"111", "222"} {
+		err := db.AddVoicemail(model.Voicemail{
+			Caller: "0301234567", Called: called, Date: date, Duration: 12 * time.Second,
+		}, []byte("audio"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var last request
+	for i := 0; i < 2; i++ {
+		select {
+		case last = <-requests:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Webhook not called (attempt %d)", i+1)
+		}
+	}
+	select {
+	case r := <-requests:
+		t.Fatalf("Unexpected request: %s", r.body)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if last.header.Get("X-Voicemail-Signature") != Sign("secret", last.body) {
+		t.Errorf("Invalid signature %q", last.header.Get("X-Voicemail-Signature"))
+	}
+
+	var notification Notification
+	if err := json.Unmarshal(last.body, &notification); err != nil {
+		t.Fatal(err)
+	}
+	expected := Notification{
+		Event:    EventAdded,
+		Id:       2,
+		Caller:   "0301234567",
+		Called:   "222",
+		Mailbox:  "office",
+		Date:     date,
+		Duration: 12,
+		AudioURL: "https://voicemail.example.com/api/v1/voicemails/2/audio",
+	}
+	if !notification.Date.Equal(expected.Date) {
+		t.Errorf("Expected date %v, got %v", expected.Date, notification.Date)
+	}
+	notification.Date = date
+	if notification != expected {
+		t.Errorf("Expected %+v, got %+v", expected, notification)
+	}
+
+	var deliveries []model.Delivery
+	for i := 0; i < 100 && len(deliveries) < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+		var err error
+		if deliveries, err = db.GetDeliveries(10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(deliveries) != 2 || !deliveries[0].Success || deliveries[0].Attempt != 2 ||
+		deliveries[1].Success || deliveries[1].Error == "" || deliveries[1].Target != server.URL {
+		t.Errorf("Unexpected delivery log: %+v", deliveries)
+	}
+}
+
+func TestWebhooksStop(t *testing.T) {
+	db, tempDir := openTestDatabase(t)
+	defer os.RemoveAll(tempDir)
+
+	retryBackoff = time.Minute
+	defer func() { retryBackoff = 10 * time.Millisecond }()
+
+	requests := make(chan struct{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- struct{}{}
+		http.Error(w, "try again", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	stop := StartWebhooks(db, []Webhook{{URL: server.URL}}, nil, "")
+	err := db.AddVoicemail(model.Voicemail{Caller: "0301234567", Called: "111", Date: time.Now()}, []byte("audio"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-requests:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Webhook not called")
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop waits for the retry delay")
+	}
+
+	// The attempt is logged before stop returns.
+	deliveries, err := db.GetDeliveries(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Success {
+		t.Errorf("Unexpected delivery log: %+v", deliveries)
+	}
+	if len(requests) != 0 {
+		t.Errorf("Unexpected retry")
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// credentials are the user and groups the process runs as.
+type credentials struct {
+	Uid, Euid int
+	Gid, Egid int
+	Groups    []int
+}
+
+func currentCredentials() (credentials, error) {
+	groups, err := syscall.Getgroups()
+	if err != nil {
+		return credentials{}, err
+	}
+	return credentials{os.Getuid(), os.Geteuid(), os.Getgid(), os.Getegid(), groups}, nil
+}
+
+// lookupUser returns the uid and gid of a user.
+func lookupUser(name string) (int, int, error) {
+	u, err := user.Lookup(name)
+	if err != nil {
+		return 0, 0, err
+	}
+	uid, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return 0, 0, fmt.Errorf("user %s has invalid uid %q", name, u.Uid)
+	}
+	gid, err := strconv.Atoi(u.Gid)
+	if err != nil {
+		return 0, 0, fmt.Errorf("user %s has invalid gid %q", name, u.Gid)
+	}
+	return uid, gid, nil
+}
+
+// jailPath returns the path of a file after chrooting into root.
+func jailPath(root string, file string) (string, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(absRoot, absFile)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", fmt.Errorf("%s is outside of %s", file, root)
+	}
+	return filepath.Join("/", rel), nil
+}
+
+// dropPrivileges switches from root to a user without supplementary
+// groups.  If root is not empty, the process is confined to that
+// directory first.  Processes not started as root keep their
+// credentials.  It fails if the process would still run as root.
+func dropPrivileges(username string, root string) error {
+	if os.Geteuid() != 0 {
+		if root != "" {
+			return errors.New("chroot requires starting as root")
+		}
+		return nil
+	}
+
+	uid, gid, err := lookupUser(username)
+	if err != nil {
+		return err
+	}
+	if uid == 0 {
+		return fmt.Errorf("refusing to run as root, set -user to an unprivileged user")
+	}
+
+	if err := syscall.Setgroups([]int{}); err != nil {
+		return err
+	}
+	if root != "" {
+		// The time zone is loaded on first use from /etc/localtime,
+		// which is not available in the chroot.
+		_ = time.Local.String()
+		if err := syscall.Chroot(root); err != nil {
+			return err
+		}
+		if err := os.Chdir("/"); err != nil {
+			return err
+		}
+	}
+	if err := syscall.Setgid(gid); err != nil {
+		return err
+	}
+	if err := syscall.Setuid(uid); err != nil {
+		return err
+	}
+	return verifyCredentials(uid, gid)
+}
+
+// verifyCredentials checks that the process runs as uid and gid only
+// and cannot become root again.
+func verifyCredentials(uid int, gid int) error {
+	c, err := currentCredentials()
+	if err != nil {
+		return err
+	}
+	if c.Uid != uid || c.Euid != uid || c.Gid != gid || c.Egid != gid {
+		return fmt.Errorf("running as uid %d/%d and gid %d/%d instead of %d:%d",
+			c.Uid, c.Euid, c.Gid, c.Egid, uid, gid)
+	}
+	for _, group := range c.Groups {
+		if group != gid {
+			return fmt.Errorf("still member of group %d", group)
+		}
+	}
+	if syscall.Setuid(0) == nil {
+		return errors.New("able to become root again")
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestJailPath(t *testing.T) {
+	for _, test := range []struct {
+		root, file, expected string
+	}{
+		{"/var/db/voicemail", "/var/db/voicemail/voicemail.sqlite", "/voicemail.sqlite"},
+		{"/var/db/voicemail/", "/var/db/voicemail/mp3/", "/mp3"},
+		{"/var/db/voicemail", "/var/db/voicemail", "/"},
+		{"/var/db/voicemail", "/var/db/voicemail2/voicemail.sqlite", ""},
+		{"/var/db/voicemail", "/var/db/voicemail/../voicemail.sqlite", ""},
+	} {
+		jailed, err := jailPath(test.root, test.file)
+		if jailed != test.expected || (err != nil) != (test.expected == "") {
+			t.Errorf("jailPath(%q, %q) = %q, %v, expected %q", test.root, test.file, jailed, err, test.expected)
+		}
+	}
+}
+
+// TestDropPrivilegesHelper drops the privileges of a separate process
+// started by TestDropPrivileges and prints the resulting credentials.
+func TestDropPrivilegesHelper(t *testing.T) {
+	username, ok := os.LookupEnv("VOICEMAIL_TEST_USER")
+	if !ok {
+		t.Skip("Started by TestDropPrivileges")
+	}
+	if err := dropPrivileges(username, os.Getenv("VOICEMAIL_TEST_CHROOT")); err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	c, err := currentCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = os.Stat("/inside")
+	data, _ := json.Marshal(struct {
+		credentials
+		Inside bool
+	}{c, err == nil})
+	fmt.Println("credentials:", string(data))
+}
+
+func TestDropPrivileges(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("Only root can drop privileges")
+	}
+	uid, gid, err := lookupUser("nobody")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	tempDir, err := ioutil.TempDir("", "voicemail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	os.Chmod(tempDir, 0755)
+	if err := ioutil.WriteFile(path.Join(tempDir, "inside"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(username string, root string) string {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestDropPrivilegesHelper$")
+		cmd.Env = append(os.Environ(), "VOICEMAIL_TEST_USER="+username, "VOICEMAIL_TEST_CHROOT="+root)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%v: %s", err, out)
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			if strings.HasPrefix(line, "credentials: ") || strings.HasPrefix(line, "error: ") {
+				return line
+			}
+		}
+		t.Fatalf("Unexpected output %s", out)
+		return ""
+	}
+
+	for _, root := range []string{"", tempDir} {
+		var result struct {
+			credentials
+			Inside bool
+		}
+		line := run("nobody", root)
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "credentials: ")), &result); err != nil {
+			t.Fatalf("Unexpected result %q", line)
+		}
+		if result.Uid != uid || result.Euid != uid || result.Gid != gid || result.Egid != gid {
+			t.Errorf("Unexpected credentials %+v", result.credentials)
+		}
+		for _, group := range result.Groups {
+			if group != gid {
+				t.Errorf("Supplementary groups not cleared: %v", result.Groups)
+			}
+		}
+		if result.Inside != (root != "") {
+			t.Errorf("Unexpected root directory for chroot %q", root)
+		}
+	}
+
+	if line := run("root", ""); !strings.Contains(line, "refusing to run as root") {
+		t.Errorf("Running as root not refused: %q", line)
+	}
+}
@@ -1,17 +1,290 @@
 package utils
 
 import (
-	"log"
+	"fmt"
+	"io"
 	"log/syslog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
-func Logger(prefix string) *log.Logger {
-	logger, err := syslog.NewLogger(syslog.LOG_NOTICE, log.Lshortfile)
+// Level is the severity of a log message.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+func (l Level) String() string {
+	if l < LevelDebug || l > LevelError {
+		return "level" + strconv.Itoa(int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel returns the level with the given name.
+func ParseLevel(name string) (Level, error) {
+	for i, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return Level(i), nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", name)
+}
+
+// LogConfig selects where log messages are written.
+type LogConfig struct {
+	// Output is "stderr", "syslog" or the name of a log file.
+	Output string
+	// Level is the lowest level that is logged.
+	Level Level
+	// MaxSize is the size in bytes at which a log file is rotated.
+	// Log files are never rotated if it is 0.
+	MaxSize int64
+	// MaxFiles is the number of rotated log files that are kept.
+	MaxFiles int
+}
+
+// backend writes formatted log lines.
+type backend interface {
+	write(level Level, line string) error
+	close() error
+}
+
+var (
+	output   backend = streamBackend{os.Stderr}
+	minLevel         = LevelInfo
+	// outputLock guards output and minLevel.
+	outputLock sync.RWMutex
+)
+
+// SetupLogging replaces the log output of all loggers.  It may be
+// called again, e.g. to reopen a log file.
+func SetupLogging(config LogConfig) error {
+	var b backend
+	switch config.Output {
+	case "", "stderr":
+		b = streamBackend{os.Stderr}
+	case "syslog":
+		w, err := syslog.New(syslog.LOG_NOTICE|syslog.LOG_DAEMON, filepath.Base(os.Args[0]))
+		if err != nil {
+			return err
+		}
+		b = syslogBackend{w}
+	default:
+		f, err := openLogFile(config.Output, config.MaxSize, config.MaxFiles)
+		if err != nil {
+			return err
+		}
+		b = f
+	}
+
+	outputLock.Lock()
+	old := output
+	output, minLevel = b, config.Level
+	outputLock.Unlock()
+
+	return old.close()
+}
+
+// Log writes messages of a component.  Messages carry key/value fields,
+// e.g. the remote address of a connection or the id of a voicemail.
+type Log struct {
+	component string
+	fields    []interface{}
+}
+
+// Logger returns the logger of a component.  Its messages go to stderr
+// until SetupLogging is called.
+func Logger(component string) *Log {
+	return &Log{component: component}
+}
+
+// With returns a logger that adds key/value fields to every message.
+func (l *Log) With(keyvals ...interface{}) *Log {
+	fields := make([]interface{}, 0, len(l.fields)+len(keyvals))
+	fields = append(append(fields, l.fields...), keyvals...)
+	return &Log{component: l.component, fields: fields}
+}
+
+func (l *Log) Debug(msg string, keyvals ...interface{}) { l.output(LevelDebug, msg, keyvals) }
+func (l *Log) Info(msg string, keyvals ...interface{})  { l.output(LevelInfo, msg, keyvals) }
+func (l *Log) Warn(msg string, keyvals ...interface{})  { l.output(LevelWarn, msg, keyvals) }
+func (l *Log) Error(msg string, keyvals ...interface{}) { l.output(LevelError, msg, keyvals) }
+
+// Print logs at info level like log.Print.
+func (l *Log) Print(v ...interface{}) { l.output(LevelInfo, fmt.Sprint(v...), nil) }
+
+// Printf logs at info level like log.Printf.
+func (l *Log) Printf(format string, v ...interface{}) {
+	l.output(LevelInfo, fmt.Sprintf(format, v...), nil)
+}
+
+// Panic logs at error level and panics.
+func (l *Log) Panic(v ...interface{}) {
+	msg := fmt.Sprint(v...)
+	l.output(LevelError, msg, nil)
+	panic(msg)
+}
+
+// Fatal logs at error level and exits.
+func (l *Log) Fatal(v ...interface{}) {
+	l.output(LevelError, fmt.Sprint(v...), nil)
+	os.Exit(1)
+}
+
+// output formats and writes a message.  It must be called directly by
+// the exported methods, so that the caller is found.
+func (l *Log) output(level Level, msg string, keyvals []interface{}) {
+	outputLock.RLock()
+	defer outputLock.RUnlock()
+	if level < minLevel {
+		return
+	}
+
+	line := "[" + l.component + "] "
+	if _, file, n, ok := runtime.Caller(2); ok {
+		line += filepath.Base(file) + ":" + strconv.Itoa(n) + ": "
+	}
+	line += strings.TrimRight(msg, "\n") + formatFields(append(l.fields[:len(l.fields):len(l.fields)], keyvals...))
+
+	if err := output.write(level, line); err != nil {
+		fmt.Fprintf(os.Stderr, "%s (unable to log: %v)\n", line, err)
+	}
+}
+
+// formatFields formats key/value pairs as " key=value".  Values with
+// spaces, quotes or equal signs are quoted.
+func formatFields(keyvals []interface{}) string {
+	s := ""
+	for i := 0; i < len(keyvals); i += 2 {
+		value := "MISSING"
+		if i+1 < len(keyvals) {
+			value = fmt.Sprint(keyvals[i+1])
+		}
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = strconv.Quote(value)
+		}
+		s += fmt.Sprintf(" %v=%s", keyvals[i], value)
+	}
+	return s
+}
+
+// streamBackend writes lines with time and level to a stream.
+type streamBackend struct {
+	w io.Writer
+}
+
+func formatLine(level Level, line string) []byte {
+	return []byte(fmt.Sprintf("%s %-5s %s\n",
+		time.Now().Format("2006-01-02 15:04:05.000"), strings.ToUpper(level.String()), line))
+}
+
+func (b streamBackend) write(level Level, line string) error {
+	_, err := b.w.Write(formatLine(level, line))
+	return err
+}
+
+func (b streamBackend) close() error {
+	return nil
+}
+
+type syslogBackend struct {
+	w *syslog.Writer
+}
+
+func (b syslogBackend) write(level Level, line string) error {
+	switch level {
+	case LevelDebug:
+		return b.w.Debug(line)
+	case LevelInfo:
+		return b.w.Notice(line)
+	case LevelWarn:
+		return b.w.Warning(line)
+	default:
+		return b.w.Err(line)
+	}
+}
+
+func (b syslogBackend) close() error {
+	return b.w.Close()
+}
+
+// fileBackend writes to a log file and rotates it when it reaches
+// maxSize: file.1 is renamed to file.2 and so on, file becomes file.1.
+type fileBackend struct {
+	sync.Mutex
+	name     string
+	maxSize  int64
+	maxFiles int
+	f        *os.File
+	size     int64
+}
+
+func openLogFile(name string, maxSize int64, maxFiles int) (*fileBackend, error) {
+	b := &fileBackend{name: name, maxSize: maxSize, maxFiles: maxFiles}
+	return b, b.open()
+}
+
+func (b *fileBackend) open() error {
+	f, err := os.OpenFile(b.name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0640)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
 	if err != nil {
-		panic(err)
+		f.Close()
+		return err
 	}
+	b.f, b.size = f, info.Size()
+	return nil
+}
 
-	logger.SetPrefix("[" + prefix + "] ")
+func (b *fileBackend) rotate() error {
+	if err := b.f.Close(); err != nil {
+		return err
+	}
+	os.Remove(b.name + "." + strconv.Itoa(b.maxFiles))
+	for i := b.maxFiles - 1; i > 0; i-- {
+		os.Rename(b.name+"."+strconv.Itoa(i), b.name+"."+strconv.Itoa(i+1))
+	}
+	if b.maxFiles > 0 {
+		if err := os.Rename(b.name, b.name+".1"); err != nil {
+			return err
+		}
+	} else if err := os.Remove(b.name); err != nil {
+		return err
+	}
+	return b.open()
+}
+
+func (b *fileBackend) write(level Level, line string) error {
+	b.Lock()
+	defer b.Unlock()
+
+	data := formatLine(level, line)
+	if b.maxSize > 0 && b.size > 0 && b.size+int64(len(data)) > b.maxSize {
+		if err := b.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := b.f.Write(data)
+	b.size += int64(n)
+	return err
+}
 
-	return logger
+func (b *fileBackend) close() error {
+	b.Lock()
+	defer b.Unlock()
+	return b.f.Close()
 }
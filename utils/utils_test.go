@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "voicemail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	defer SetupLogging(LogConfig{Output: "stderr", Level: LevelInfo})
+
+	file := path.Join(tempDir, "voicemail.log")
+	if err := SetupLogging(LogConfig{Output: file, Level: LevelInfo, MaxSize: 300, MaxFiles: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := Logger("test").With("remote", "192.0.2.1:1234")
+	logger.Debug("Not logged")
+	logger.Info("Received", "voicemail", 12, "caller", "Max Mustermann")
+	logger.Printf("Printed %d", 1)
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", lines)
+	}
+	if !strings.HasSuffix(lines[0], `INFO  [test] utils_test.go:26: Received remote=192.0.2.1:1234 voicemail=12 caller="Max Mustermann"`) {
+		t.Errorf("Unexpected line %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "[test] utils_test.go:27: Printed 1 remote=192.0.2.1:1234") {
+		t.Errorf("Unexpected line %q", lines[1])
+	}
+
+	for i := 0; i < 10; i++ {
+		logger.Warn("Rotate")
+	}
+	for _, name := range []string{file, file + ".1", file + ".2"} {
+		if info, err := os.Stat(name); err != nil || info.Size() > 300 {
+			t.Errorf("Unexpected log file %s: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(file + ".3"); !os.IsNotExist(err) {
+		t.Errorf("Too many log files kept: %v", err)
+	}
+
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("Unknown level accepted")
+	}
+	if level, err := ParseLevel("WARN"); err != nil || level != LevelWarn {
+		t.Errorf("Unexpected level %v: %v", level, err)
+	}
+}
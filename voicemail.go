@@ -1,62 +1,209 @@
 package main
 
 import (
+	"context"
 	"flag"
-	"log"
+	"fmt"
 	"net"
-	"os/user"
-	"strconv"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 
 	"./mail"
 	"./model"
+	"./notify"
 	"./utils"
 	"./web"
 )
 
-var logger *log.Logger = utils.Logger("voicemail")
+var logger = utils.Logger("voicemail")
 
 func main() {
-	var Hostname, User, DatabaseFile, VoicemailDirectory, HttpPort, SmtpPort string
-	var Limit int
-
-	flag.StringVar(&Hostname, "host", "localhost", "Hostname or IP to bind to")
-	flag.StringVar(&User, "user", "nobody", "User to drop to after binding")
-	flag.StringVar(&DatabaseFile, "database", "./voicemail.sqlite", "Database file location")
-	flag.StringVar(&VoicemailDirectory, "voicemail", "./mp3/", "Voicemail storage directory")
-	flag.StringVar(&HttpPort, "http-port", "8080", "Port for the HTTP service")
-	flag.StringVar(&SmtpPort, "smtp-port", "2500", "Port for the SMTP service")
-	flag.IntVar(&Limit, "limit", -1, "Only display this many voicemails in the web interface")
-
+	var config Config
+	config.flags(flag.CommandLine)
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [options] [command]\n\nOptions:\n", os.Args[0])
+		flag.PrintDefaults()
+		printCommands()
+	}
 	flag.Parse()
+	if err := config.load(flag.CommandLine, os.LookupEnv); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 
-	smtpListener, err := net.Listen("tcp", Hostname+":"+SmtpPort)
+	sections, err := config.loadSections()
 	if err != nil {
-		logger.Panic(err)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if flag.NArg() > 0 {
+		db := model.OpenDatabase(config.Database, config.Voicemail)
+		os.Exit(runCommand(db, config, sections, flag.Args()))
 	}
 
-	httpListener, err := net.Listen("tcp", Hostname+":"+HttpPort)
+	if err := utils.SetupLogging(config.logConfig()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	smtpListener, httpListener, err := listen(config)
 	if err != nil {
 		logger.Panic(err)
 	}
 
-	u, err := user.Lookup(User)
-	if err != nil {
+	if config.Chroot != "" {
+		if config.Database, err = jailPath(config.Chroot, config.Database); err != nil {
+			logger.Panic(err)
+		}
+		if config.Voicemail, err = jailPath(config.Chroot, config.Voicemail); err != nil {
+			logger.Panic(err)
+		}
+	}
+	if err := dropPrivileges(config.User, config.Chroot); err != nil {
 		logger.Panic(err)
 	}
 
-	uid, _ := strconv.Atoi(u.Uid)
-	gid, _ := strconv.Atoi(u.Gid)
+	db := model.OpenDatabase(config.Database, config.Voicemail)
+	stop := start(db, config, sections)
+	go func() {
+		if err := mail.CheckTranscoder(); err != nil {
+			logger.Warn("Unable to convert audio", "error", err)
+		}
+	}()
 
-	if err = syscall.Setgid(gid); err != nil {
-		logger.Panic(err)
+	var handler atomic.Value
+	handler.Store(newHandler(db, config, sections))
+	ctx, cancel := context.WithCancel(context.Background())
+	httpServer := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handler.Load().(http.Handler).ServeHTTP(w, r)
+		}),
+		// Event streams end when their request context is canceled.
+		BaseContext: func(net.Listener) context.Context { return ctx },
 	}
-	if err = syscall.Setuid(uid); err != nil {
-		logger.Panic(err)
+	httpServer.RegisterOnShutdown(cancel)
+
+	smtpDone := make(chan struct{})
+	go func() {
+		if err := mail.Serve(smtpListener, db); err != nil {
+			logger.Fatal(err)
+		}
+		close(smtpDone)
+	}()
+	go func() {
+		if err := httpServer.Serve(httpListener); err != http.ErrServerClosed {
+			logger.Fatal(err)
+		}
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM)
+	var sig os.Signal
+	for sig = range signals {
+		if sig != syscall.SIGHUP {
+			break
+		}
+		if config.Chroot != "" {
+			logger.Warn("The configuration cannot be reloaded in a chroot, restart instead")
+			continue
+		}
+
+		newConfig, newSections, err := reloadConfig()
+		if err != nil {
+			logger.Error("Unable to reload the configuration", "error", err)
+			continue
+		}
+		if err := utils.SetupLogging(newConfig.logConfig()); err != nil {
+			logger.Error("Unable to reopen the log", "error", err)
+		}
+		if changed := config.restartRequired(newConfig); len(changed) > 0 {
+			logger.Warn("Some changes take effect after a restart", "settings", strings.Join(changed, ","))
+		}
+		newConfig.Host, newConfig.User, newConfig.Database, newConfig.Voicemail =
+			config.Host, config.User, config.Database, config.Voicemail
+		newConfig.HTTPPort, newConfig.SMTPPort = config.HTTPPort, config.SMTPPort
+		newConfig.Chroot = config.Chroot
+		config, sections = newConfig, newSections
+
+		// The new services get the events that arrive while the old
+		// ones are stopped.
+		release := db.HoldEvents()
+		stop()
+		stop = start(db, config, sections)
+		release()
+		handler.Store(newHandler(db, config, sections))
+		logger.Info("Configuration reloaded")
 	}
 
-	db := model.OpenDatabase(DatabaseFile, VoicemailDirectory)
+	logger.Info("Shutting down", "signal", sig)
+	smtpListener.Close()
+	ctx, cancel = context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(ctx); err != nil {
+		logger.Warn("Unable to finish HTTP requests", "error", err)
+	}
+	select {
+	case <-smtpDone:
+	case <-ctx.Done():
+		logger.Warn("Unable to finish receiving voicemail", "error", ctx.Err())
+	}
+	// Notifications and hooks in progress still log to the database.
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		logger.Warn("Unable to finish notifications and hooks", "error", ctx.Err())
+	}
+	if err := db.Close(); err != nil {
+		logger.Error("Unable to close database", "error", err)
+	}
+}
+
+// newHandler returns the web interface.  /readyz checks that voicemails
+// can be received, converted and stored.
+func newHandler(db model.Database, config Config, sections Sections) http.Handler {
+	minFree := uint64(config.MinFreeSpace) * 1024 * 1024
+	checks := map[string]web.Check{
+		"storage":    func() error { return db.CheckStorage(minFree) },
+		"transcoder": mail.CheckTranscoder,
+		"smtp":       mail.CheckListener,
+	}
+	return web.Handler(db, config.Voicemail, config.Limit, sections.Mailboxes, config.PublicURL, checks)
+}
 
-	go mail.Serve(smtpListener, db)
-	web.Serve(httpListener, db, VoicemailDirectory, Limit)
+// start starts the background services that depend on reloadable
+// settings.  The returned function stops them and waits for the
+// deliveries and hooks in progress.
+func start(db model.Database, config Config, sections Sections) func() {
+	mailboxes := sections.Mailboxes
+	config.Retention.MaxSize = config.RetentionMaxSize * 1024 * 1024
+	stops := []func(){
+		db.StartRetention(config.RetentionInterval, mailboxes.RetentionRules(config.Retention)...),
+		notify.StartWebhooks(db, sections.Webhooks, mailboxes, config.PublicURL),
+		notify.StartEmail(db, sections.Email, mailboxes, config.Voicemail, config.PublicURL),
+		notify.StartMQTT(db, sections.MQTT, mailboxes, config.PublicURL),
+		notify.StartHooks(db, sections.Hooks, mailboxes, config.Voicemail, config.PublicURL),
+		mail.StartPoll(db, sections.Poll),
+	}
+	return func() {
+		var wg sync.WaitGroup
+		for _, stop := range stops {
+			wg.Add(1)
+			go func(stop func()) {
+				defer wg.Done()
+				stop()
+			}(stop)
+		}
+		wg.Wait()
+	}
 }
@@ -0,0 +1,195 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"../model"
+	"../web/assets"
+)
+
+const apiPrefix = "/api/v1/"
+
+// apiVoicemail is the JSON representation of a voicemail.
+type apiVoicemail struct {
+	Id      int       `json:"id"`
+	Caller  string    `json:"caller"`
+	Called  string    `json:"called"`
+	Mailbox string    `json:"mailbox,omitempty"`
+	Date    time.Time `json:"date"`
+	// Duration is given in seconds.
+	Duration   float64  `json:"duration"`
+	Read       bool     `json:"read"`
+	Starred    bool     `json:"starred"`
+	Archived   bool     `json:"archived"`
+	Deleted    bool     `json:"deleted"`
+	Notes      string   `json:"notes"`
+	Transcript string   `json:"transcript"`
+	Tags       []string `json:"tags"`
+	Audio      string   `json:"audio"`
+}
+
+type apiPage struct {
+	Voicemails []apiVoicemail `json:"voicemails"`
+	Older      model.Cursor   `json:"older,omitempty"`
+	Newer      model.Cursor   `json:"newer,omitempty"`
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func newAPIVoicemail(voicemail model.Voicemail, mailboxes model.Mailboxes) apiVoicemail {
+	mailbox, _ := mailboxes.ForNumber(voicemail.Called)
+	return apiVoicemail{
+		Id:         voicemail.Id,
+		Caller:     voicemail.Caller,
+		Called:     voicemail.Called,
+		Mailbox:    mailbox.Name,
+		Date:       voicemail.Date,
+		Duration:   voicemail.Duration.Seconds(),
+		Read:       voicemail.Read,
+		Starred:    voicemail.Starred,
+		Archived:   voicemail.Archived,
+		Deleted:    voicemail.Deleted,
+		Notes:      voicemail.Notes,
+		Transcript: voicemail.Transcript,
+		Tags:       voicemail.Tags,
+		Audio:      apiPrefix + "voicemails/" + strconv.Itoa(voicemail.Id) + "/audio",
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Warn("Unable to write JSON response", "error", err)
+	}
+}
+
+func writeJSONError(w http.ResponseWriter, status int, err interface{}) {
+	writeJSON(w, status, apiError{fmt.Sprint(err)})
+}
+
+func apiListHandler(db model.Database, limit int, mailboxes model.Mailboxes) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		query := r.URL.Query()
+		if s := query.Get("limit"); s != "" {
+			var err error
+			if limit, err = strconv.Atoi(s); err != nil || limit < 1 {
+				writeJSONError(w, http.StatusBadRequest, "invalid limit")
+				return
+			}
+		}
+
+		q, err := parseQuery(query, mailboxes, permissions(r, mailboxes), limit)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		page, err := db.GetPage(q)
+		if err == model.ErrInvalidCursor {
+			writeJSONError(w, http.StatusBadRequest, err)
+			return
+		} else if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		result := apiPage{Voicemails: []apiVoicemail{}, Older: page.Older, Newer: page.Newer}
+		for _, voicemail := range page.Voicemails {
+			result.Voicemails = append(result.Voicemails, newAPIVoicemail(voicemail, mailboxes))
+		}
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+// apiVoicemailHandler serves /api/v1/voicemails/{id} and the
+// operations below it.
+func apiVoicemailHandler(db model.Database, voicemailDir string, mailboxes model.Mailboxes) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, apiPrefix+"voicemails/"), "/")
+		id, err := strconv.Atoi(parts[0])
+		if err != nil || len(parts) > 2 {
+			writeJSONError(w, http.StatusNotFound, "not found")
+			return
+		}
+		operation := ""
+		if len(parts) == 2 {
+			operation = parts[1]
+		}
+
+		voicemail, err := db.GetVoicemail(id)
+		if err == model.ErrNoVoicemail || (err == nil && !permissions(r, mailboxes).Allows(voicemail.Called)) {
+			writeJSONError(w, http.StatusNotFound, model.ErrNoVoicemail)
+			return
+		} else if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		switch {
+		case operation == "" && r.Method == "GET":
+			writeJSON(w, http.StatusOK, newAPIVoicemail(voicemail, mailboxes))
+			return
+
+		case operation == "" && r.Method == "DELETE":
+			if r.URL.Query().Get("purge") == "true" {
+				err = db.PurgeVoicemail(id)
+			} else {
+				err = db.DeleteVoicemail(id)
+			}
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+
+		case operation == "audio" && r.Method == "GET":
+			w.Header().Set("Content-Type", "audio/mpeg")
+			http.ServeFile(w, r, path.Join(voicemailDir, path.Base(voicemail.VoicemailPath)))
+			return
+
+		case (operation == "read" || operation == "unread") && r.Method == "POST":
+			if operation == "read" {
+				err = db.MarkRead(id)
+			} else {
+				err = db.MarkUnread(id)
+			}
+			if err == nil {
+				voicemail, err = db.GetVoicemail(id)
+			}
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, newAPIVoicemail(voicemail, mailboxes))
+			return
+
+		case operation == "" || operation == "audio" || operation == "read" || operation == "unread":
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		writeJSONError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func registerAPI(mux *http.ServeMux, db model.Database, voicemailDir string, limit int, mailboxes model.Mailboxes) {
+	mux.HandleFunc(apiPrefix+"openapi.json",
+		handleAsset(assets.Openapi_json, "application/json"))
+	mux.HandleFunc(apiPrefix+"voicemails", apiListHandler(db, limit, mailboxes))
+	mux.HandleFunc(apiPrefix+"voicemails/", apiVoicemailHandler(db, voicemailDir, mailboxes))
+}
@@ -0,0 +1,447 @@
+package web
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"../model"
+)
+
+type apiTest struct {
+	t       *testing.T
+	db      model.Database
+	handler http.Handler
+	session model.Session
+}
+
+func newAPITest(t *testing.T, tempDir string, grants ...string) *apiTest {
+	db := model.OpenDatabase(path.Join(tempDir, "voicemail.sqlite"), tempDir)
+	mailboxes := model.Mailboxes{
+		{Name: "home", Numbers: []string{"111"}},
+		{Name: "office", Numbers: []string{"222"}},
+	}
+
+	for i, called := range []string{"111", "222", "111"} {
+		err := db.AddVoicemail(model.Voicemail{
+			Caller:   "0301234567" + strconv.Itoa(i),
+			Called:   called,
+			Date:     time.Now().Add(time.Duration(i) * time.Minute),
+			Duration: 5 * time.Second,
+		}, []byte("audio"+strconv.Itoa(i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := db.CreateUser("test", "password", grants); err != nil {
+		t.Fatal(err)
+	}
+	user, err := db.Authenticate("test", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := db.CreateSession(user, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &apiTest{t, db, Handler(db, tempDir, 2, mailboxes, "", nil), session}
+}
+
+func (a *apiTest) request(method string, url string, authenticated bool) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, url, nil)
+	if authenticated {
+		r.AddCookie(&http.Cookie{Name: sessionCookie, Value: a.session.Token})
+		r.Header.Set("X-CSRF-Token", a.session.CSRFToken)
+	}
+	w := httptest.NewRecorder()
+	a.handler.ServeHTTP(w, r)
+	return w
+}
+
+func (a *apiTest) decode(w *httptest.ResponseRecorder, status int, v interface{}) {
+	if w.Code != status {
+		a.t.Fatalf("Expected status %d, got %d: %s", status, w.Code, w.Body.String())
+	}
+	reflect.ValueOf(v).Elem().Set(reflect.Zero(reflect.TypeOf(v).Elem()))
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		a.t.Fatalf("Invalid JSON response %q: %v", w.Body.String(), err)
+	}
+}
+
+func TestAPI(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "voicemail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	a := newAPITest(t, tempDir, model.AllMailboxes)
+
+	var e apiError
+	a.decode(a.request("GET", "/api/v1/voicemails", false), http.StatusUnauthorized, &e)
+
+	var page apiPage
+	a.decode(a.request("GET", "/api/v1/voicemails", true), http.StatusOK, &page)
+	if len(page.Voicemails) != 2 || page.Older == "" || page.Newer != "" {
+		t.Fatalf("Unexpected first page: %+v", page)
+	}
+	if page.Voicemails[0].Caller != "03012345672" || page.Voicemails[0].Mailbox != "home" {
+		t.Errorf("Unexpected first voicemail: %+v", page.Voicemails[0])
+	}
+
+	a.decode(a.request("GET", "/api/v1/voicemails?before="+string(page.Older), true), http.StatusOK, &page)
+	if len(page.Voicemails) != 1 || page.Older != "" || page.Newer == "" {
+		t.Fatalf("Unexpected second page: %+v", page)
+	}
+	id := strconv.Itoa(page.Voicemails[0].Id)
+
+	a.decode(a.request("GET", "/api/v1/voicemails?mailbox=office&limit=10", true), http.StatusOK, &page)
+	if len(page.Voicemails) != 1 || page.Voicemails[0].Called != "222" {
+		t.Errorf("Unexpected mailbox page: %+v", page)
+	}
+	a.decode(a.request("GET", "/api/v1/voicemails?limit=0", true), http.StatusBadRequest, &e)
+	a.decode(a.request("GET", "/api/v1/voicemails?folder=spam", true), http.StatusBadRequest, &e)
+
+	var voicemail apiVoicemail
+	a.decode(a.request("GET", "/api/v1/voicemails/"+id, true), http.StatusOK, &voicemail)
+	if voicemail.Caller != "03012345670" || voicemail.Duration != 5 || voicemail.Read {
+		t.Errorf("Unexpected voicemail: %+v", voicemail)
+	}
+	a.decode(a.request("GET", "/api/v1/voicemails/9999", true), http.StatusNotFound, &e)
+
+	a.decode(a.request("POST", "/api/v1/voicemails/"+id+"/read", true), http.StatusOK, &voicemail)
+	if !voicemail.Read {
+		t.Errorf("Voicemail not marked as read: %+v", voicemail)
+	}
+	a.decode(a.request("GET", "/api/v1/voicemails?read=unread", true), http.StatusOK, &page)
+	if len(page.Voicemails) != 2 || page.Older != "" {
+		t.Errorf("Unexpected unread page: %+v", page)
+	}
+
+	w := a.request("GET", voicemail.Audio, true)
+	if w.Code != http.StatusOK || w.Body.String() != "audio0" {
+		t.Errorf("Unexpected audio response %d: %q", w.Code, w.Body.String())
+	}
+
+	r := httptest.NewRequest("DELETE", "/api/v1/voicemails/"+id, nil)
+	r.AddCookie(&http.Cookie{Name: sessionCookie, Value: a.session.Token})
+	w = httptest.NewRecorder()
+	a.handler.ServeHTTP(w, r)
+	a.decode(w, http.StatusForbidden, &e)
+
+	if w := a.request("DELETE", "/api/v1/voicemails/"+id, true); w.Code != http.StatusNoContent {
+		t.Errorf("Unexpected delete response %d: %s", w.Code, w.Body.String())
+	}
+	a.decode(a.request("GET", "/api/v1/voicemails/"+id, true), http.StatusOK, &voicemail)
+	if !voicemail.Deleted {
+		t.Errorf("Voicemail not deleted: %+v", voicemail)
+	}
+
+	if w := a.request("DELETE", "/api/v1/voicemails/"+id+"?purge=true", true); w.Code != http.StatusNoContent {
+		t.Errorf("Unexpected purge response %d: %s", w.Code, w.Body.String())
+	}
+	a.decode(a.request("GET", "/api/v1/voicemails/"+id, true), http.StatusNotFound, &e)
+
+	var spec map[string]interface{}
+	a.decode(a.request("GET", "/api/v1/openapi.json", false), http.StatusOK, &spec)
+	if spec["openapi"] == nil {
+		t.Errorf("Unexpected OpenAPI description: %v", spec)
+	}
+}
+
+func TestAPIPermissions(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "voicemail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	a := newAPITest(t, tempDir, "office")
+
+	var page apiPage
+	a.decode(a.request("GET", "/api/v1/voicemails", true), http.StatusOK, &page)
+	if len(page.Voicemails) != 1 || page.Voicemails[0].Called != "222" {
+		t.Fatalf("Unexpected page: %+v", page)
+	}
+
+	a.decode(a.request("GET", "/api/v1/voicemails?mailbox=home", true), http.StatusOK, &page)
+	if len(page.Voicemails) != 0 {
+		t.Errorf("Unexpected page for forbidden mailbox: %+v", page)
+	}
+
+	var e apiError
+
+	a.decode(a.request("GET", "/api/v1/voicemails/1", true), http.StatusNotFound, &e)
+	a.decode(a.request("GET", "/api/v1/voicemails/1/audio", true), http.StatusNotFound, &e)
+	a.decode(a.request("POST", "/api/v1/voicemails/1/read", true), http.StatusNotFound, &e)
+	a.decode(a.request("DELETE", "/api/v1/voicemails/1", true), http.StatusNotFound, &e)
+}
+
+func TestAPITokens(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "voicemail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	a := newAPITest(t, tempDir, "office")
+	_, readToken, err := a.db.CreateToken("test", "read", []model.Scope{model.ScopeRead})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, writeToken, err := a.db.CreateToken("test", "write", []model.Scope{model.ScopeWrite})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := func(method string, url string, token string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, url, nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		a.handler.ServeHTTP(w, r)
+		return w
+	}
+
+	var page apiPage
+	a.decode(request("GET", "/api/v1/voicemails", readToken), http.StatusOK, &page)
+	if len(page.Voicemails) != 1 || page.Voicemails[0].Called != "222" {
+		t.Fatalf("Unexpected page: %+v", page)
+	}
+	id := strconv.Itoa(page.Voicemails[0].Id)
+
+	var e apiError
+	a.decode(request("GET", "/api/v1/voicemails", "vm_invalid"), http.StatusUnauthorized, &e)
+	a.decode(request("POST", "/api/v1/voicemails/"+id+"/read", readToken), http.StatusForbidden, &e)
+
+	var voicemail apiVoicemail
+	a.decode(request("POST", "/api/v1/voicemails/"+id+"/read", writeToken), http.StatusOK, &voicemail)
+	if !voicemail.Read {
+		t.Errorf("Voicemail not marked as read: %+v", voicemail)
+	}
+
+	if w := request("GET", "/tokens", writeToken); w.Code != http.StatusForbidden {
+		t.Errorf("Token management allowed without admin scope: %d", w.Code)
+	}
+	if w := a.request("GET", "/tokens", true); w.Code != http.StatusOK ||
+		!strings.Contains(w.Body.String(), "write") {
+		t.Errorf("Unexpected token page %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestEvents(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "voicemail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	a := newAPITest(t, tempDir, "office")
+	server := httptest.NewServer(a.handler)
+	defer server.Close()
+
+	r, _ := http.NewRequest("GET", server.URL+"/events", nil)
+	r.AddCookie(&http.Cookie{Name: sessionCookie, Value: a.session.Token})
+	res, err := http.DefaultClient.Do(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK || res.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Unexpected response %d %s", res.StatusCode, res.Header.Get("Content-Type"))
+	}
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	// The first voicemail is in a mailbox the user may not access,
+	// failures are not streamed.
+	a.db.ReportFailure(model.Voicemail{Caller: "0301234567", Called: "222"}, errors.New("lame not found"))
+	if err := a.db.MarkRead(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.db.MarkRead(2); err != nil {
+		t.Fatal(err)
+	}
+
+	var event, data string
+	for event == "" || data == "" {
+		select {
+		case line := <-lines:
+			if strings.HasPrefix(line, "event: ") {
+				event = strings.TrimPrefix(line, "event: ")
+			} else if strings.HasPrefix(line, "data: ") {
+				data = strings.TrimPrefix(line, "data: ")
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("No event received")
+		}
+	}
+
+	var voicemail apiVoicemail
+	if err := json.Unmarshal([]byte(data), &voicemail); err != nil {
+		t.Fatal(err)
+	}
+	if event != "changed" || voicemail.Id != 2 || !voicemail.Read {
+		t.Errorf("Unexpected %s event: %+v", event, voicemail)
+	}
+}
+
+func TestFeeds(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "voicemail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	a := newAPITest(t, tempDir, model.AllMailboxes)
+	_, token, err := a.db.CreateToken("test", "feed", []model.Scope{model.ScopeFeed})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var feed rss
+	w := a.request("GET", "/feed/"+token+"/home/rss.xml", false)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Unexpected status %d: %s", w.Code, w.Body.String())
+	}
+	if err := xml.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+		t.Fatal(err)
+	}
+	if len(feed.Channel.Items) != 2 {
+		t.Fatalf("Unexpected RSS feed: %s", w.Body.String())
+	}
+	item := feed.Channel.Items[0]
+	if !strings.Contains(w.Body.String(), "<itunes:duration>00:00:05</itunes:duration>") {
+		t.Errorf("Missing duration in RSS feed: %s", w.Body.String())
+	}
+	if item.Title != "Anruf von 03012345672" ||
+		item.GUID.IsPermaLink || !strings.HasPrefix(item.GUID.Value, "urn:voicemail:") ||
+		item.Enclosure.Length != 6 || item.Enclosure.Type != "audio/mpeg" {
+		t.Errorf("Unexpected RSS item: %+v", item)
+	}
+	if !strings.HasPrefix(item.Enclosure.URL, "http://example.com/feed/"+token+"/audio/") {
+		t.Errorf("Unexpected enclosure URL: %s", item.Enclosure.URL)
+	}
+
+	w = a.request("GET", strings.TrimPrefix(item.Enclosure.URL, "http://example.com"), false)
+	if w.Code != http.StatusOK || w.Body.String() != "audio2" {
+		t.Errorf("Unexpected enclosure %d: %q", w.Code, w.Body.String())
+	}
+
+	var atom atomFeed
+	w = a.request("GET", "/feed/"+token+"/atom.xml", false)
+	if err := xml.Unmarshal(w.Body.Bytes(), &atom); err != nil {
+		t.Fatal(err)
+	}
+	if len(atom.Entries) != 3 || len(atom.Entries[0].Link) != 2 ||
+		atom.Entries[0].Link[0].Rel != "alternate" || atom.Entries[0].Link[1].Rel != "enclosure" ||
+		!strings.HasPrefix(atom.Entries[0].Link[0].Href, "http://example.com/api/v1/voicemails/") {
+		t.Errorf("Unexpected Atom feed: %s", w.Body.String())
+	}
+
+	// Feed tokens only open feeds, other tokens do not open them.
+	r := httptest.NewRequest("GET", "/api/v1/voicemails", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	a.handler.ServeHTTP(w, r)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("API served with feed token: %d", w.Code)
+	}
+	for _, scope := range []model.Scope{model.ScopeRead, model.ScopeWrite, model.ScopeAdmin} {
+		_, other, err := a.db.CreateToken("test", string(scope), []model.Scope{scope})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w := a.request("GET", "/feed/"+other+"/rss.xml", false); w.Code != http.StatusForbidden {
+			t.Errorf("Feed served with %s token: %d", scope, w.Code)
+		}
+	}
+
+	if w := a.request("GET", "/feed/vm_invalid/rss.xml", false); w.Code != http.StatusUnauthorized {
+		t.Errorf("Feed served with invalid token: %d", w.Code)
+	}
+	if w := a.request("GET", "/feed/"+token+"/unknown/rss.xml", false); w.Code != http.StatusNotFound {
+		t.Errorf("Feed served for unknown mailbox: %d", w.Code)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "voicemail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	a := newAPITest(t, tempDir, model.AllMailboxes)
+	if w := a.request("GET", "/metrics", false); w.Code != http.StatusUnauthorized {
+		t.Errorf("Metrics served without login: %d", w.Code)
+	}
+	a.request("GET", "/voicemails", true)
+
+	w := a.request("GET", "/metrics", true)
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Fatalf("Unexpected response %d: %s", w.Code, w.Body.String())
+	}
+	for _, line := range []string{
+		`voicemail_http_requests_total{method="GET",code="401"} `,
+		`voicemail_http_requests_total{method="GET",code="200"} `,
+		"\nvoicemail_audio_stored_bytes_total ",
+		"# TYPE voicemail_http_request_duration_seconds histogram\n",
+	} {
+		if !strings.Contains(w.Body.String(), line) {
+			t.Errorf("Missing %q in metrics: %s", line, w.Body.String())
+		}
+	}
+}
+
+func TestHealth(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "voicemail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	a := newAPITest(t, tempDir, model.AllMailboxes)
+	failing := errors.New("not accepting")
+	a.handler = Handler(a.db, tempDir, 2, nil, "", map[string]Check{
+		"storage": func() error { return nil },
+		"smtp":    func() error { return failing },
+	})
+
+	var h health
+	a.decode(a.request("GET", "/healthz", false), http.StatusOK, &h)
+	if h.Status != "ok" || len(h.Checks) != 1 || h.Checks["database"] != "ok" {
+		t.Errorf("Unexpected health %+v", h)
+	}
+
+	a.decode(a.request("GET", "/readyz", false), http.StatusServiceUnavailable, &h)
+	if h.Status != "failed" || len(h.Checks) != 3 || h.Checks["storage"] != "ok" ||
+		h.Checks["smtp"] != "not accepting" {
+		t.Errorf("Unexpected readiness %+v", h)
+	}
+
+	failing = nil
+	a.decode(a.request("GET", "/readyz", false), http.StatusOK, &h)
+	if h.Status != "ok" {
+		t.Errorf("Unexpected readiness %+v", h)
+	}
+}
@@ -22,164 +22,384 @@ func app_html() []byte {
 }
 
 var app_html_gz []byte = []byte{
-  0x1f, 0x8b, 0x08, 0x08, 0x4f, 0x7a, 0x6a, 0x57, 0x00, 0x03, 0x61, 0x70,
-  0x70, 0x2e, 0x68, 0x74, 0x6d, 0x6c, 0x00, 0xed, 0x59, 0x7b, 0x6b, 0xe4,
-  0x36, 0x10, 0xff, 0x7f, 0x3f, 0x85, 0x4e, 0x2d, 0x65, 0x17, 0x62, 0x3b,
-  0x49, 0xef, 0xd5, 0x7d, 0xc1, 0x35, 0x97, 0xd2, 0x83, 0xeb, 0xf5, 0xa0,
-  0x47, 0xa1, 0x1c, 0x47, 0xd1, 0xda, 0xda, 0xb5, 0xee, 0x6c, 0xc9, 0x95,
-  0xe5, 0x4d, 0xd2, 0x65, 0xff, 0xeb, 0x37, 0xeb, 0x17, 0xeb, 0x8c, 0xe4,
-  0x87, 0x76, 0xbd, 0xce, 0xa5, 0x14, 0x0a, 0x85, 0x06, 0x12, 0xcb, 0xd2,
-  0x3c, 0x34, 0x33, 0xbf, 0x19, 0x8d, 0x9c, 0xdd, 0x2e, 0xe1, 0x6b, 0x21,
-  0x39, 0xa1, 0x31, 0xcb, 0xb2, 0x92, 0xee, 0xf7, 0xa3, 0xf9, 0xa3, 0x97,
-  0x3f, 0x5e, 0xbd, 0xfb, 0xe5, 0xed, 0x35, 0x49, 0x4d, 0x9e, 0x2d, 0x47,
-  0x73, 0x7c, 0x90, 0x8c, 0xc9, 0xcd, 0x82, 0x26, 0x9c, 0x2e, 0x47, 0x84,
-  0xcc, 0x53, 0xce, 0x12, 0x1c, 0xc0, 0x30, 0xe7, 0x86, 0x91, 0x38, 0x65,
-  0xba, 0xe4, 0x66, 0x41, 0x2b, 0xb3, 0x0e, 0x9e, 0x53, 0x7f, 0x29, 0x35,
-  0xa6, 0x08, 0xf8, 0x6f, 0x95, 0xd8, 0x2e, 0xe8, 0x95, 0x92, 0x86, 0x4b,
-  0x13, 0xbc, 0xbb, 0x2b, 0x38, 0x25, 0xb1, 0x7b, 0x5b, 0x50, 0xc3, 0x6f,
-  0x4d, 0x84, 0x5a, 0x66, 0xad, 0xa0, 0x03, 0x39, 0x46, 0x98, 0x8c, 0x2f,
-  0x5f, 0x48, 0x5d, 0xad, 0x57, 0x9c, 0x49, 0x73, 0xa3, 0xb4, 0xe1, 0x7a,
-  0x1e, 0xb9, 0x79, 0x4f, 0x97, 0x64, 0x39, 0xc7, 0x4d, 0x96, 0xb1, 0x16,
-  0x85, 0x11, 0x4a, 0x7a, 0x4a, 0x68, 0x9f, 0x90, 0x55, 0x26, 0x55, 0xfa,
-  0x33, 0x34, 0x45, 0x91, 0xf1, 0x20, 0x57, 0x2b, 0x01, 0x8f, 0x1b, 0xbe,
-  0x0a, 0x60, 0x22, 0x88, 0x59, 0xc1, 0x56, 0x99, 0x6f, 0xc2, 0x1d, 0x2f,
-  0x4f, 0x30, 0xaf, 0x95, 0xce, 0x99, 0x09, 0x12, 0x6e, 0x78, 0x7c, 0xb4,
-  0x1d, 0xc3, 0x33, 0x5e, 0xa4, 0x4a, 0xf2, 0x85, 0x54, 0x94, 0x44, 0xcb,
-  0x91, 0x63, 0x7e, 0x14, 0x04, 0xe4, 0x35, 0x27, 0xdf, 0xbf, 0xfb, 0xe1,
-  0xf5, 0x13, 0x52, 0xa6, 0x22, 0x3f, 0x23, 0x20, 0x84, 0xbc, 0xba, 0x7e,
-  0x1a, 0x3c, 0x27, 0x65, 0x55, 0x14, 0x60, 0x3a, 0x51, 0x6b, 0x4b, 0x40,
-  0x40, 0x44, 0x0e, 0xc2, 0x4a, 0x12, 0x04, 0xcb, 0x96, 0xfd, 0xbd, 0x58,
-  0x93, 0xcc, 0x00, 0x07, 0xf9, 0xe6, 0x83, 0x9b, 0xb5, 0x2b, 0xce, 0x25,
-  0xa4, 0xd4, 0xf1, 0x82, 0x62, 0x48, 0xa6, 0x91, 0xf5, 0xf8, 0x13, 0xd4,
-  0x11, 0x6e, 0x94, 0xda, 0x64, 0x3c, 0x56, 0x09, 0x0f, 0x63, 0x95, 0x47,
-  0xe5, 0x56, 0x46, 0x46, 0x57, 0xf2, 0x93, 0x23, 0x09, 0x3f, 0x82, 0x6d,
-  0xf3, 0xc8, 0x49, 0xf0, 0x44, 0x3e, 0x7a, 0xcf, 0x65, 0x22, 0xd6, 0x1f,
-  0x50, 0xbb, 0x53, 0x9f, 0x09, 0xf9, 0x89, 0xa4, 0x9a, 0xaf, 0x17, 0x34,
-  0x8a, 0xcb, 0x92, 0x12, 0xcd, 0xb3, 0x05, 0x2d, 0xcd, 0x5d, 0xc6, 0xcb,
-  0x94, 0x73, 0xd3, 0xb8, 0xc8, 0xce, 0x10, 0x03, 0x28, 0xa8, 0x83, 0x8f,
-  0xc4, 0x8d, 0xe4, 0x95, 0x4a, 0xee, 0xc8, 0xae, 0x55, 0x53, 0xb0, 0x24,
-  0x11, 0x72, 0x13, 0x18, 0x55, 0x4c, 0xc9, 0xd3, 0xf3, 0xe2, 0x76, 0xd6,
-  0x5b, 0x5a, 0x29, 0x63, 0x54, 0x3e, 0x25, 0x8f, 0xbd, 0xd5, 0x7d, 0xfd,
-  0xac, 0x1f, 0x61, 0x29, 0x12, 0xbe, 0x62, 0x3a, 0x90, 0x6c, 0xdb, 0x17,
-  0x3e, 0x25, 0xdf, 0x14, 0xb7, 0xe4, 0xfc, 0x98, 0x37, 0x2c, 0x32, 0x76,
-  0x77, 0x46, 0xc2, 0xa4, 0xd2, 0x0c, 0xc3, 0xe7, 0x31, 0x12, 0x72, 0x23,
-  0x12, 0x93, 0x4e, 0xc9, 0x13, 0x9e, 0xf7, 0xd8, 0x12, 0x66, 0xf8, 0x29,
-  0xda, 0x8b, 0xf3, 0x13, 0xc4, 0xa8, 0x23, 0xb0, 0x8e, 0xf3, 0x39, 0xd0,
-  0x2b, 0x01, 0xcb, 0xc4, 0x46, 0x4e, 0x89, 0x16, 0x9b, 0xd4, 0xf4, 0xf8,
-  0x0c, 0x22, 0x90, 0x18, 0xeb, 0x2d, 0xa3, 0xa7, 0xa9, 0xda, 0x72, 0x4d,
-  0x4c, 0x72, 0x36, 0xb4, 0x92, 0x1e, 0xc8, 0x27, 0x2b, 0x16, 0x7f, 0xda,
-  0x68, 0x55, 0xc9, 0x24, 0x88, 0x55, 0xa6, 0xf4, 0x94, 0xdc, 0xa4, 0xc2,
-  0xf0, 0x43, 0x35, 0x10, 0x74, 0x8c, 0xd4, 0x41, 0x78, 0x5d, 0x48, 0x21,
-  0x6f, 0x4c, 0x5c, 0x19, 0x22, 0x62, 0x44, 0xb5, 0x0b, 0xb9, 0xc8, 0x37,
-  0x91, 0x4b, 0x17, 0xa3, 0xaa, 0x38, 0x0d, 0x70, 0x2d, 0x2c, 0xe4, 0xa6,
-  0x89, 0x7a, 0xc7, 0x7e, 0x4c, 0xf5, 0x50, 0x09, 0x98, 0x1d, 0xf7, 0x8a,
-  0x29, 0xc5, 0xef, 0xbc, 0x5c, 0xd0, 0x67, 0x97, 0xb7, 0xcf, 0x2e, 0x3b,
-  0xa1, 0x6c, 0xc3, 0xcb, 0x9e, 0xdc, 0xc0, 0x12, 0x3d, 0x74, 0x7f, 0xb5,
-  0xe0, 0x8b, 0x8b, 0xc7, 0xb7, 0xf0, 0xfb, 0x39, 0xd1, 0x35, 0x99, 0x13,
-  0x4e, 0xba, 0xfc, 0xc0, 0xfd, 0xbf, 0x48, 0x12, 0x72, 0x5b, 0x30, 0x88,
-  0x47, 0x93, 0xce, 0x46, 0x41, 0xb6, 0x86, 0xe4, 0xa5, 0xca, 0x85, 0x84,
-  0xca, 0xc1, 0x79, 0x52, 0x42, 0xb8, 0x44, 0x3f, 0xad, 0x5f, 0x5d, 0x9f,
-  0xc8, 0x68, 0x2f, 0x8b, 0x3e, 0xb2, 0x2d, 0x73, 0xb3, 0xd4, 0x25, 0xfa,
-  0xc7, 0x32, 0xb2, 0xaa, 0x1e, 0x98, 0xc1, 0xf3, 0xc8, 0xd5, 0x75, 0x1c,
-  0x22, 0x7a, 0x6a, 0xed, 0x89, 0xd8, 0x92, 0x38, 0x63, 0x25, 0xd8, 0x0f,
-  0xd9, 0x03, 0x49, 0x44, 0xdc, 0x23, 0x58, 0x8b, 0x5b, 0x9e, 0x60, 0x62,
-  0xba, 0x43, 0xa1, 0x47, 0x17, 0x08, 0x29, 0xb9, 0xa6, 0x7d, 0x31, 0x58,
-  0x05, 0x19, 0x9c, 0x3a, 0x20, 0x22, 0xab, 0x44, 0xd2, 0xa6, 0xfe, 0x9c,
-  0x35, 0x14, 0x2b, 0xcd, 0x64, 0xd2, 0xb8, 0x39, 0xa2, 0x27, 0x8a, 0x3f,
-  0xab, 0x9d, 0x0a, 0x5c, 0x55, 0x46, 0x44, 0x62, 0x95, 0x8a, 0x0d, 0xab,
-  0xeb, 0x6c, 0xbb, 0x0f, 0xda, 0xd9, 0xbb, 0xdb, 0x81, 0x13, 0xc3, 0x37,
-  0xfc, 0x66, 0xbf, 0x87, 0x60, 0x2f, 0x41, 0x9b, 0x93, 0xff, 0x85, 0xe4,
-  0x37, 0x74, 0xf9, 0x86, 0x57, 0x9c, 0xbc, 0x61, 0x71, 0xaa, 0x45, 0x9c,
-  0x42, 0x91, 0x46, 0x15, 0xf3, 0x08, 0xe8, 0x76, 0x3b, 0xf0, 0xd2, 0x7e,
-  0x7f, 0x24, 0xe6, 0xc7, 0x2c, 0x39, 0x16, 0xa3, 0x32, 0x30, 0xe5, 0x45,
-  0x66, 0x1e, 0x22, 0x66, 0x1e, 0x55, 0xd9, 0xd2, 0x33, 0xa0, 0xdb, 0x30,
-  0x29, 0xaa, 0x2c, 0x0b, 0x6c, 0xd2, 0x7b, 0x7b, 0x07, 0x4d, 0xc4, 0x26,
-  0x23, 0x9c, 0x6f, 0xa2, 0xc4, 0xa2, 0x31, 0x25, 0x12, 0x8e, 0x8f, 0x19,
-  0xb5, 0xb6, 0xe3, 0x04, 0xd7, 0x57, 0x8d, 0x5f, 0x3d, 0xc6, 0x26, 0x6d,
-  0xd2, 0xaf, 0x9d, 0x13, 0xbd, 0x85, 0x2d, 0x94, 0xb4, 0x79, 0x59, 0x30,
-  0x49, 0x56, 0x42, 0x26, 0x0e, 0x44, 0x53, 0x3c, 0x98, 0xd6, 0x90, 0x76,
-  0x39, 0x9c, 0x06, 0x59, 0xe8, 0xe4, 0xfe, 0x8a, 0xcd, 0xc1, 0xaf, 0xb8,
-  0x6c, 0x61, 0x04, 0x1c, 0xf0, 0x00, 0x81, 0x9e, 0xac, 0x06, 0xab, 0x4d,
-  0x20, 0xab, 0x44, 0x28, 0x6f, 0x67, 0xd4, 0xaf, 0x3d, 0xee, 0xc7, 0x01,
-  0xd7, 0x12, 0x46, 0x79, 0xc1, 0x37, 0x27, 0x48, 0x2c, 0x88, 0x23, 0x34,
-  0xf3, 0x56, 0x94, 0x78, 0x6e, 0x1e, 0xda, 0x15, 0x59, 0x66, 0xcf, 0x47,
-  0xe8, 0xe6, 0x51, 0xcf, 0xc1, 0xf3, 0x08, 0xa0, 0x67, 0x11, 0xea, 0x06,
-  0xf5, 0xe3, 0xe1, 0xa8, 0xf4, 0x68, 0xb4, 0xba, 0x39, 0x5a, 0x3d, 0x5c,
-  0x47, 0xdf, 0x5c, 0x5c, 0x1e, 0x6e, 0x13, 0x97, 0xd1, 0x13, 0xf5, 0xd9,
-  0x0f, 0x8b, 0x1e, 0x10, 0xa1, 0xbd, 0xba, 0x44, 0x08, 0xb9, 0x86, 0xe1,
-  0x1e, 0x20, 0x02, 0xd9, 0x68, 0xee, 0x4a, 0x7b, 0xad, 0xcb, 0xbe, 0xb8,
-  0xd4, 0x33, 0x5e, 0x43, 0x66, 0x74, 0xbb, 0x71, 0x93, 0x02, 0x23, 0xfc,
-  0xf1, 0xde, 0x5f, 0x32, 0x53, 0xe5, 0xfd, 0xc9, 0xca, 0xf6, 0x53, 0x07,
-  0x93, 0x16, 0x2d, 0xfd, 0xe9, 0xee, 0x1d, 0x46, 0xda, 0xb9, 0xb5, 0x55,
-  0x3f, 0x37, 0x75, 0xdd, 0x00, 0x13, 0x21, 0x83, 0x37, 0xbc, 0xb1, 0xf2,
-  0x70, 0x67, 0x73, 0x93, 0x34, 0x46, 0x20, 0x3c, 0x7c, 0x5f, 0xae, 0x2a,
-  0x38, 0xcb, 0xa5, 0xbf, 0x1a, 0x6c, 0x95, 0x88, 0x79, 0xce, 0x44, 0x16,
-  0xac, 0x8c, 0x0c, 0x00, 0x9d, 0x86, 0xc0, 0x00, 0x7f, 0x83, 0xb2, 0x8a,
-  0x63, 0x0e, 0xbd, 0x43, 0x0f, 0x3a, 0x70, 0x04, 0xb3, 0x8e, 0x71, 0x41,
-  0x77, 0xbb, 0xf0, 0xe7, 0xe6, 0xed, 0x2d, 0x54, 0xc4, 0xfd, 0xfe, 0x30,
-  0x46, 0xa2, 0xd1, 0x68, 0xeb, 0xb7, 0xdb, 0xd4, 0x3c, 0x12, 0x3e, 0xb6,
-  0xdc, 0xce, 0x5a, 0x57, 0x44, 0x26, 0xe9, 0xdc, 0xd2, 0x9a, 0x83, 0x47,
-  0x3f, 0x85, 0x54, 0x0f, 0xc1, 0xcf, 0x3c, 0xfc, 0xce, 0xf6, 0x7f, 0x84,
-  0x9e, 0x5f, 0x86, 0xe7, 0x17, 0xe1, 0xe5, 0xf9, 0xf9, 0x53, 0x72, 0xf1,
-  0x64, 0x7a, 0xfe, 0x18, 0xda, 0xec, 0x21, 0xfe, 0xba, 0xd5, 0x70, 0x32,
-  0xea, 0x97, 0x41, 0x6a, 0x4c, 0x4b, 0x4c, 0x76, 0xa0, 0xbd, 0xb2, 0xc3,
-  0x41, 0xca, 0xb6, 0xcb, 0xa0, 0x4d, 0x15, 0xe9, 0x77, 0x18, 0xc3, 0x61,
-  0x40, 0x77, 0x9f, 0x08, 0x85, 0xab, 0x51, 0xff, 0xd4, 0xf7, 0x2f, 0x56,
-  0x65, 0x21, 0xa0, 0xe8, 0xc8, 0x07, 0xf8, 0xda, 0x41, 0xae, 0xa9, 0xa4,
-  0xf0, 0xe6, 0xe0, 0x06, 0x03, 0xcc, 0x85, 0x6e, 0x65, 0xe4, 0xd5, 0xe8,
-  0xc3, 0x0c, 0xbb, 0xa7, 0x46, 0xff, 0x57, 0x33, 0xcc, 0x59, 0xf9, 0x7f,
-  0x86, 0xfd, 0x9f, 0x61, 0xff, 0x66, 0x86, 0xd9, 0xae, 0xc2, 0xfb, 0x19,
-  0x79, 0x5d, 0x4c, 0x01, 0x6d, 0xb1, 0xeb, 0x41, 0xb0, 0x6d, 0x69, 0x26,
-  0x35, 0xdf, 0x0a, 0x55, 0xb5, 0xf7, 0xe4, 0xb6, 0x6d, 0xa2, 0x75, 0xfb,
-  0x11, 0x67, 0x22, 0xfe, 0xd4, 0xf6, 0x1f, 0x78, 0x5a, 0x6a, 0x85, 0x2e,
-  0x0f, 0x21, 0x67, 0xb1, 0x07, 0xc1, 0x65, 0x0e, 0xd9, 0xfb, 0x55, 0xc6,
-  0xb4, 0x9e, 0x91, 0x3f, 0xff, 0x80, 0x2c, 0xd6, 0xdc, 0x76, 0x82, 0xcd,
-  0xd9, 0x7f, 0xa0, 0x50, 0xda, 0x7e, 0xe5, 0xef, 0x2a, 0x83, 0x23, 0xd8,
-  0x57, 0x86, 0x87, 0xb1, 0xe6, 0xe4, 0x2b, 0x8d, 0x3a, 0x0f, 0x74, 0xb9,
-  0x06, 0xa3, 0x6d, 0xee, 0x5b, 0x3f, 0x78, 0x98, 0x6f, 0x1a, 0x8f, 0x83,
-  0x57, 0xbf, 0xab, 0xc0, 0xb6, 0x40, 0xd9, 0x2f, 0x15, 0xb6, 0x41, 0xf7,
-  0xc8, 0xe7, 0x6b, 0xa5, 0xc0, 0x3c, 0x98, 0xac, 0x07, 0x23, 0xaf, 0x97,
-  0x71, 0x2d, 0x5d, 0xd4, 0x36, 0x2c, 0xee, 0x8a, 0xf1, 0x90, 0x1b, 0x41,
-  0x04, 0x57, 0x82, 0xdf, 0x79, 0x61, 0x54, 0x08, 0x97, 0x8d, 0xa3, 0x6b,
-  0xc1, 0xfd, 0xfc, 0xcb, 0xd1, 0x16, 0x3a, 0xff, 0xb8, 0xd2, 0x1a, 0x7a,
-  0x98, 0xec, 0xee, 0x2d, 0x80, 0x15, 0x6e, 0xce, 0x64, 0x41, 0x24, 0xb4,
-  0xab, 0x33, 0xbb, 0xe8, 0x5a, 0x3d, 0x98, 0x4a, 0x54, 0x5c, 0xe1, 0x97,
-  0x89, 0x70, 0xc3, 0xcd, 0xb5, 0xfb, 0x48, 0xf1, 0xed, 0xdd, 0xab, 0x64,
-  0xdc, 0x34, 0x83, 0x93, 0xd9, 0x68, 0xb4, 0xae, 0xa4, 0xfd, 0x26, 0x02,
-  0x37, 0xae, 0x92, 0x9b, 0x6f, 0x2d, 0x1c, 0xcb, 0xf1, 0xa4, 0xbe, 0xa5,
-  0x8a, 0xf5, 0xb8, 0xaf, 0x6a, 0xe1, 0x94, 0x4d, 0x80, 0xc5, 0x54, 0x5a,
-  0xce, 0x9c, 0x4b, 0x9c, 0xcc, 0xb0, 0x60, 0x55, 0xc9, 0xc7, 0x93, 0x99,
-  0x3f, 0x07, 0x26, 0xb7, 0x1b, 0x6c, 0x63, 0x73, 0x2c, 0x37, 0xc4, 0x7a,
-  0xe7, 0xf4, 0xb7, 0xa1, 0x0a, 0x35, 0xcf, 0xe1, 0xda, 0x7c, 0x85, 0x40,
-  0x1a, 0x63, 0x72, 0x06, 0x09, 0xd6, 0x5a, 0xd8, 0x79, 0x47, 0xc2, 0x92,
-  0xc4, 0x5b, 0x6f, 0xea, 0xe4, 0x64, 0x40, 0x46, 0xab, 0x35, 0xc8, 0x14,
-  0xc3, 0x6f, 0x0e, 0x3e, 0xe5, 0x1a, 0x20, 0x39, 0xa6, 0x62, 0x90, 0xd9,
-  0x96, 0xcc, 0x12, 0x2f, 0x5a, 0x27, 0xf5, 0x77, 0x15, 0xb5, 0x36, 0xbf,
-  0x67, 0xa2, 0xad, 0x23, 0xce, 0xc6, 0x10, 0x03, 0x3b, 0xa6, 0x6d, 0x4d,
-  0x40, 0x9e, 0xbd, 0x17, 0x0e, 0x94, 0x33, 0x76, 0x37, 0x8c, 0x33, 0xd2,
-  0xb9, 0xe6, 0x8c, 0x78, 0x32, 0xbc, 0x28, 0x75, 0x14, 0x61, 0xca, 0xca,
-  0x61, 0x63, 0x27, 0xde, 0xe7, 0x87, 0x26, 0x7c, 0x38, 0xde, 0xbb, 0x20,
-  0x1e, 0x82, 0x60, 0x36, 0x18, 0xd8, 0x46, 0xad, 0xdb, 0xa0, 0x2f, 0xf4,
-  0x04, 0x32, 0x77, 0xdd, 0xe6, 0xa6, 0x43, 0xa6, 0x4c, 0xfd, 0x97, 0xbd,
-  0xf7, 0x6d, 0xc9, 0x47, 0x90, 0x67, 0x24, 0x96, 0xde, 0x31, 0x6d, 0x6b,
-  0x6f, 0xe3, 0x71, 0x8f, 0x05, 0x4d, 0xc6, 0xed, 0x76, 0xe6, 0xf6, 0x9c,
-  0xff, 0x13, 0x84, 0xb2, 0x70, 0xae, 0x6f, 0x88, 0x4e, 0x80, 0xf0, 0x28,
-  0xca, 0xf7, 0x21, 0xe8, 0x34, 0x66, 0xfb, 0x98, 0x3c, 0x01, 0xdc, 0x1e,
-  0xb0, 0x07, 0x10, 0x39, 0x80, 0x4a, 0x07, 0xbb, 0x21, 0x05, 0x1e, 0x70,
-  0x9b, 0x70, 0x43, 0xc0, 0x6b, 0x47, 0x01, 0xd9, 0xf5, 0x16, 0x2c, 0x7a,
-  0x6d, 0xef, 0x76, 0x5c, 0x8f, 0x29, 0x1c, 0x2d, 0x50, 0x73, 0xcf, 0x48,
-  0x03, 0xc6, 0xb6, 0x1c, 0xe0, 0xe7, 0x90, 0x5e, 0x36, 0x1f, 0x63, 0x66,
-  0x8f, 0x4e, 0x1f, 0x94, 0x1d, 0x33, 0x69, 0xf7, 0x3a, 0x24, 0xdd, 0xe2,
-  0x7e, 0x28, 0x7f, 0x3c, 0x00, 0x7c, 0x2e, 0xab, 0xeb, 0x7d, 0x7c, 0x39,
-  0xa6, 0xe1, 0x40, 0x6f, 0x45, 0x27, 0xa1, 0x3d, 0x5e, 0xc6, 0xed, 0x46,
-  0x78, 0xb3, 0x13, 0xac, 0xa2, 0x1e, 0x5c, 0xc0, 0xdc, 0x2f, 0xc7, 0x6e,
-  0x77, 0x0c, 0xf5, 0xbc, 0xc1, 0x2f, 0xb4, 0xdd, 0x70, 0x52, 0x87, 0xe9,
-  0x94, 0x22, 0xd7, 0x39, 0x78, 0x99, 0x34, 0x76, 0x92, 0x26, 0x5d, 0x68,
-  0xbd, 0x84, 0xf5, 0x42, 0x79, 0xd6, 0xc5, 0xb2, 0xe6, 0xf0, 0x66, 0xfc,
-  0x1a, 0x70, 0xbf, 0xa1, 0xb5, 0xfe, 0x7b, 0x2d, 0xed, 0xbc, 0xfa, 0x0f,
-  0x0c, 0x75, 0x1e, 0xf5, 0xec, 0xf4, 0x62, 0xf5, 0x70, 0x53, 0xbd, 0xea,
-  0xd0, 0xb3, 0xbf, 0xb6, 0xd4, 0x3b, 0x28, 0xa3, 0xa6, 0x29, 0x72, 0xff,
-  0x21, 0x69, 0x7a, 0xa2, 0xbf, 0x00, 0xd5, 0x9c, 0xda, 0x6f, 0x4d, 0x19,
-  0x00, 0x00,
+  0x1f, 0x8b, 0x08, 0x08, 0x90, 0xc6, 0xd5, 0x6a, 0x02, 0xff, 0x61, 0x70,
+  0x70, 0x2e, 0x68, 0x74, 0x6d, 0x6c, 0x00, 0xdd, 0x5b, 0x5b, 0x93, 0xdb,
+  0xb8, 0xb1, 0x7e, 0xd7, 0xaf, 0x80, 0x19, 0xd7, 0x94, 0x54, 0x35, 0x24,
+  0xc7, 0xce, 0xee, 0x26, 0x91, 0x34, 0x4a, 0x8d, 0xed, 0xd9, 0xc4, 0x15,
+  0xdb, 0xeb, 0x5a, 0x3b, 0x9b, 0x3a, 0xbb, 0xb5, 0x95, 0x82, 0x48, 0x48,
+  0x84, 0x87, 0x37, 0x83, 0xa0, 0xe6, 0x16, 0xbd, 0xe5, 0x37, 0xe5, 0x29,
+  0x6f, 0xfe, 0x63, 0xa7, 0x1b, 0xe0, 0x05, 0xbc, 0x4a, 0xf6, 0x3a, 0x39,
+  0xde, 0xe3, 0xaa, 0xb1, 0x24, 0x12, 0xe8, 0x6e, 0x34, 0xba, 0x3f, 0x74,
+  0xa3, 0x81, 0xfb, 0x7b, 0x9f, 0x6d, 0x78, 0xcc, 0x88, 0xe5, 0xd1, 0x30,
+  0xcc, 0xac, 0xfd, 0x7e, 0xb2, 0x7c, 0xf0, 0xec, 0xbb, 0xa7, 0x6f, 0xff,
+  0xe7, 0xf5, 0x25, 0x09, 0x64, 0x14, 0xae, 0x26, 0x4b, 0xfc, 0x20, 0x21,
+  0x8d, 0xb7, 0xe7, 0x96, 0xcf, 0xac, 0xd5, 0x84, 0x90, 0x65, 0xc0, 0xa8,
+  0x8f, 0x5f, 0xe0, 0x6b, 0xc4, 0x24, 0x25, 0x5e, 0x40, 0x45, 0xc6, 0xe4,
+  0xb9, 0x95, 0xcb, 0x8d, 0xfd, 0x7b, 0xcb, 0x7c, 0x15, 0x48, 0x99, 0xda,
+  0xec, 0x7d, 0xce, 0x77, 0xe7, 0xd6, 0xd3, 0x24, 0x96, 0x2c, 0x96, 0xf6,
+  0xdb, 0xdb, 0x94, 0x59, 0xc4, 0xd3, 0xbf, 0xce, 0x2d, 0xc9, 0x6e, 0xa4,
+  0x8b, 0x5c, 0x16, 0x15, 0xa1, 0x06, 0x1d, 0xc9, 0x65, 0xc8, 0x56, 0x17,
+  0xb1, 0xc8, 0x37, 0x6b, 0x46, 0x63, 0x79, 0x9d, 0x08, 0xc9, 0xc4, 0xd2,
+  0xd5, 0xcf, 0x0d, 0x5e, 0x31, 0x8d, 0x18, 0x0a, 0x99, 0x79, 0x82, 0xa7,
+  0x92, 0x27, 0xb1, 0xc1, 0xc4, 0xea, 0x36, 0xa4, 0xb9, 0x0c, 0x12, 0x71,
+  0xa0, 0x4d, 0x9a, 0x86, 0xcc, 0x8e, 0x92, 0x35, 0x87, 0x8f, 0x6b, 0xb6,
+  0xb6, 0xe1, 0x81, 0xed, 0xd1, 0x94, 0xae, 0x43, 0x73, 0x08, 0xb7, 0x2c,
+  0xeb, 0xe9, 0xbc, 0x49, 0x44, 0x44, 0xa5, 0xed, 0x33, 0xc9, 0xbc, 0x96,
+  0x38, 0x92, 0x85, 0x2c, 0x0d, 0x92, 0x98, 0x9d, 0xc7, 0x89, 0x45, 0xdc,
+  0x6e, 0x5f, 0x2f, 0x13, 0x1b, 0x5b, 0x26, 0x57, 0xcc, 0xec, 0x75, 0x7f,
+  0xef, 0x3c, 0x7d, 0xf3, 0xfd, 0xb7, 0xfb, 0x3d, 0x30, 0xd3, 0x3d, 0x1e,
+  0xd8, 0x36, 0x79, 0xc1, 0xc8, 0x9f, 0xdf, 0xbe, 0x7c, 0xf1, 0x35, 0xc9,
+  0x02, 0x1e, 0x9d, 0x12, 0xe0, 0x4a, 0x9e, 0x5f, 0x7e, 0x63, 0xff, 0x9e,
+  0x64, 0x79, 0x9a, 0x82, 0xae, 0x48, 0xb2, 0x51, 0x0d, 0x08, 0xf0, 0x8c,
+  0x80, 0x4e, 0x46, 0x6c, 0x7b, 0x55, 0x75, 0xff, 0x89, 0x6f, 0x48, 0x28,
+  0xa1, 0x07, 0xf9, 0xc3, 0xcf, 0xfa, 0xa9, 0x7a, 0xa3, 0x75, 0x48, 0x32,
+  0xe1, 0x9d, 0x5b, 0x38, 0x87, 0x73, 0x57, 0x4d, 0xd1, 0xd7, 0xc8, 0xc3,
+  0xd9, 0x26, 0xc9, 0x36, 0x64, 0x5e, 0xe2, 0x33, 0xc7, 0x4b, 0x22, 0x37,
+  0xdb, 0xc5, 0xae, 0x14, 0x79, 0x7c, 0xa5, 0x9b, 0x38, 0xef, 0x40, 0x19,
+  0x4b, 0x57, 0x53, 0x30, 0x48, 0x3e, 0xf8, 0x89, 0xc5, 0x3e, 0xdf, 0xfc,
+  0x8c, 0xdc, 0x35, 0xfb, 0x90, 0xc7, 0x57, 0x24, 0x10, 0x6c, 0x73, 0x6e,
+  0xb9, 0x5e, 0x96, 0x59, 0x44, 0xb0, 0xf0, 0xdc, 0xca, 0xe4, 0x6d, 0xc8,
+  0xb2, 0x80, 0x31, 0x59, 0xea, 0x54, 0x3d, 0x21, 0x12, 0xcc, 0xa6, 0xb0,
+  0x16, 0x6c, 0x5c, 0x52, 0x5e, 0x27, 0xfe, 0x2d, 0xb9, 0xaf, 0xd8, 0xa4,
+  0xd4, 0xf7, 0x79, 0xbc, 0x05, 0xdd, 0xa5, 0x73, 0xf2, 0xcd, 0x59, 0x7a,
+  0xb3, 0xe8, 0xbc, 0x5a, 0x27, 0x52, 0x26, 0xd1, 0x9c, 0x7c, 0x65, 0xbc,
+  0xdd, 0x17, 0x9f, 0xc5, 0x87, 0x93, 0x71, 0x9f, 0xad, 0xa9, 0xb0, 0x63,
+  0xba, 0xeb, 0x12, 0x9f, 0x93, 0x3f, 0xa4, 0x37, 0xe4, 0xac, 0xdd, 0xd7,
+  0x49, 0x43, 0x7a, 0x7b, 0x4a, 0x1c, 0x3f, 0x17, 0x14, 0xe7, 0xdb, 0xe8,
+  0x48, 0xc8, 0x35, 0xf7, 0x65, 0x30, 0x27, 0x5f, 0xb3, 0xa8, 0xd3, 0xcd,
+  0xa7, 0x92, 0xf5, 0xb5, 0x7d, 0x74, 0xd6, 0xd3, 0x18, 0x79, 0xd8, 0x4a,
+  0x71, 0x66, 0x0f, 0xd4, 0x8a, 0x4d, 0x43, 0xbe, 0x8d, 0xe7, 0x44, 0xf0,
+  0x6d, 0x20, 0x3b, 0xfd, 0x24, 0x9a, 0x2c, 0x91, 0x4a, 0x5b, 0x52, 0xcc,
+  0x83, 0x64, 0xc7, 0x04, 0x91, 0xfe, 0xe9, 0xd0, 0x9b, 0xa0, 0x41, 0x9f,
+  0xac, 0xa9, 0x77, 0xb5, 0x15, 0x49, 0x1e, 0xfb, 0xb6, 0x97, 0x84, 0x89,
+  0x98, 0x93, 0xeb, 0x80, 0x4b, 0xd6, 0x61, 0x43, 0x95, 0xa5, 0x67, 0xc7,
+  0x09, 0xa7, 0xc6, 0x8a, 0x64, 0xec, 0x2c, 0xa5, 0x1e, 0x9b, 0x93, 0x38,
+  0xb9, 0x16, 0x34, 0xed, 0x10, 0xcd, 0x63, 0x01, 0x88, 0x43, 0x1c, 0x04,
+  0x29, 0x90, 0xcd, 0xa4, 0xbd, 0x01, 0xdf, 0x00, 0xc7, 0x44, 0xaa, 0x73,
+  0x30, 0x85, 0xd0, 0xef, 0x74, 0x66, 0x3e, 0x97, 0xb6, 0x48, 0xae, 0x95,
+  0x18, 0x14, 0x08, 0xf5, 0xa9, 0xfa, 0xb7, 0x7d, 0xaa, 0x2e, 0xc7, 0x82,
+  0x7e, 0xdc, 0xe8, 0xe4, 0xf3, 0x0c, 0xa7, 0x61, 0x4e, 0x78, 0x0c, 0x33,
+  0xc1, 0xcc, 0xe1, 0x44, 0x54, 0x6c, 0x39, 0x8c, 0xb3, 0x65, 0x1c, 0xe0,
+  0x0e, 0x68, 0xc3, 0x0d, 0xc3, 0xd7, 0xc6, 0x0e, 0x10, 0x24, 0xbd, 0x5c,
+  0x12, 0xee, 0x21, 0x40, 0x68, 0x67, 0xe0, 0xd1, 0xd6, 0xd5, 0xc8, 0x23,
+  0x93, 0xdc, 0x0b, 0x6c, 0x7c, 0xe7, 0xa4, 0xf1, 0xb6, 0xf4, 0x87, 0xba,
+  0x7b, 0xbb, 0xd5, 0xb1, 0x14, 0x10, 0x37, 0x46, 0xc9, 0x64, 0xfc, 0x8e,
+  0x65, 0xe7, 0xd6, 0xef, 0x1e, 0xdf, 0xfc, 0xee, 0x71, 0x4d, 0x94, 0x6e,
+  0x59, 0xd6, 0xa1, 0x6b, 0xab, 0x46, 0xc7, 0xca, 0x57, 0x10, 0x7e, 0xf4,
+  0xe8, 0xab, 0x1b, 0xf8, 0x3b, 0x44, 0xba, 0x68, 0xa6, 0x89, 0x93, 0x1a,
+  0x39, 0x50, 0xfe, 0x0b, 0xdf, 0x27, 0x37, 0x29, 0x05, 0x4b, 0x2d, 0x81,
+  0x4e, 0x26, 0x80, 0x63, 0x0e, 0x79, 0x96, 0x44, 0x3c, 0x06, 0x20, 0x65,
+  0xcc, 0xcf, 0xc0, 0x90, 0x79, 0x17, 0xf0, 0x9e, 0x5f, 0xf6, 0x60, 0x9d,
+  0x81, 0x2f, 0xef, 0xe8, 0x8e, 0xea, 0xa7, 0x96, 0x86, 0xc0, 0x77, 0x99,
+  0xab, 0x58, 0x1d, 0x89, 0x6d, 0x4b, 0x57, 0x2f, 0x91, 0xf8, 0x15, 0xfd,
+  0xaa, 0xe0, 0xee, 0xf3, 0x1d, 0xf1, 0x42, 0x9a, 0xc1, 0xf8, 0x01, 0x57,
+  0x00, 0x5e, 0x88, 0xfe, 0xb0, 0x37, 0xfc, 0x86, 0xf9, 0x08, 0x59, 0x7a,
+  0x7d, 0xed, 0xb4, 0xb3, 0x79, 0x1c, 0x33, 0x61, 0x75, 0xc9, 0xe0, 0xd2,
+  0x40, 0xc1, 0x04, 0x81, 0x44, 0x98, 0x73, 0xbf, 0x02, 0xc5, 0x25, 0x2d,
+  0x5b, 0xac, 0x05, 0x8d, 0xfd, 0x52, 0xcd, 0xae, 0xd5, 0xb3, 0x8e, 0xd2,
+  0x42, 0xa9, 0xd0, 0x2b, 0x0f, 0x09, 0xf7, 0x15, 0x53, 0xbe, 0xa5, 0xc5,
+  0x92, 0x55, 0xc9, 0x61, 0x19, 0xe3, 0x0d, 0xf9, 0xfd, 0x3d, 0xe8, 0x91,
+  0xbd, 0x27, 0xce, 0xb7, 0xe0, 0x74, 0xe0, 0x94, 0x16, 0x8f, 0xd7, 0xc9,
+  0x0d, 0x44, 0x10, 0x65, 0x0f, 0xf4, 0x9e, 0x1d, 0x83, 0x35, 0x0b, 0x34,
+  0xb3, 0xdf, 0xaf, 0x40, 0xa2, 0x42, 0x06, 0xd5, 0xd3, 0x79, 0x49, 0x79,
+  0x08, 0x1d, 0xf6, 0xfb, 0x3f, 0x46, 0xfa, 0xdb, 0x39, 0xac, 0x6e, 0xd5,
+  0xc3, 0xa2, 0x97, 0xb5, 0x7a, 0x9d, 0x64, 0x92, 0x01, 0xe4, 0x42, 0xfc,
+  0x81, 0x92, 0x2e, 0xdd, 0x90, 0x8f, 0x8b, 0x41, 0x85, 0x17, 0x20, 0xdf,
+  0x23, 0x04, 0xf9, 0xe3, 0x46, 0xf5, 0x39, 0x2f, 0xba, 0xb4, 0xe4, 0x3a,
+  0xa1, 0x51, 0xba, 0x18, 0x95, 0xed, 0x42, 0xf5, 0x3b, 0x4e, 0x2c, 0x29,
+  0x68, 0x16, 0x7c, 0x8c, 0x50, 0xaa, 0xc3, 0x47, 0x8b, 0xf4, 0x9a, 0xa6,
+  0x9c, 0x89, 0xab, 0x44, 0xac, 0xbb, 0x62, 0x69, 0x62, 0xaf, 0xd8, 0xf5,
+  0x7e, 0x0f, 0x12, 0xd6, 0x2c, 0x7f, 0x13, 0xb3, 0x6b, 0x6b, 0xf5, 0x8a,
+  0xe5, 0x8c, 0xbc, 0xa2, 0x5e, 0x20, 0xb8, 0x17, 0x40, 0xb8, 0x51, 0xf5,
+  0x2f, 0x68, 0xb7, 0xc8, 0x7c, 0x17, 0xfa, 0x6d, 0x32, 0x20, 0x37, 0xe8,
+  0x24, 0x94, 0xc7, 0x90, 0x59, 0xba, 0x79, 0xb8, 0x32, 0xac, 0xae, 0xb6,
+  0x32, 0x92, 0xe6, 0x61, 0x68, 0xab, 0x65, 0xa2, 0x69, 0x70, 0x86, 0x8e,
+  0x54, 0x5c, 0x04, 0x8e, 0x78, 0xf1, 0xfa, 0xb9, 0xfd, 0x56, 0x7d, 0xef,
+  0x9d, 0x84, 0x95, 0x81, 0xcb, 0x4b, 0x05, 0xe2, 0x4d, 0xa7, 0xc2, 0x47,
+  0x16, 0x81, 0xa0, 0x2b, 0x48, 0xc0, 0xe8, 0x53, 0xb0, 0x34, 0x8b, 0x68,
+  0xcc, 0x07, 0x1e, 0x61, 0xb2, 0x4d, 0x72, 0x53, 0x04, 0x45, 0x85, 0xc7,
+  0x69, 0x5e, 0x22, 0x45, 0xc0, 0x7d, 0x1f, 0xc3, 0xb3, 0x3a, 0x60, 0xb3,
+  0xc8, 0x8e, 0x86, 0x39, 0x6b, 0x06, 0x6a, 0x8d, 0xfe, 0xeb, 0x1c, 0x42,
+  0x8f, 0xb8, 0xf2, 0x4d, 0x09, 0xdd, 0x35, 0xb1, 0x2c, 0x5f, 0x47, 0x1c,
+  0xf8, 0xab, 0xa0, 0xf6, 0xdc, 0xba, 0x58, 0x47, 0x2c, 0x44, 0xea, 0xa0,
+  0x37, 0xe7, 0xaf, 0x19, 0x13, 0x60, 0x39, 0xb4, 0x78, 0xb6, 0x74, 0x35,
+  0x95, 0xc6, 0xf0, 0x5c, 0x1c, 0x8c, 0x31, 0xfa, 0xb6, 0x2e, 0x88, 0x5a,
+  0x7f, 0x20, 0x3a, 0x2e, 0x97, 0xae, 0x18, 0x82, 0xcf, 0x85, 0xa5, 0xdc,
+  0x1d, 0x1f, 0x30, 0xf1, 0xb4, 0x84, 0x92, 0x86, 0xcc, 0x7a, 0xa5, 0x08,
+  0x7e, 0xab, 0x71, 0xc3, 0x78, 0xb1, 0x83, 0x61, 0x2c, 0x61, 0xd9, 0x8e,
+  0xc9, 0x9a, 0xc7, 0xbe, 0xc6, 0xcd, 0x39, 0x86, 0xb5, 0xb0, 0x22, 0x3b,
+  0x11, 0x84, 0x86, 0xa1, 0xa3, 0xe9, 0xfe, 0x1d, 0x57, 0xed, 0xbf, 0xe3,
+  0x6b, 0x85, 0x9c, 0xd0, 0x03, 0x3e, 0x80, 0xa0, 0x41, 0xab, 0x84, 0xe7,
+  0x12, 0xbb, 0x72, 0x9f, 0x27, 0x86, 0x64, 0x56, 0x43, 0x87, 0x3a, 0xa4,
+  0x50, 0x4a, 0x53, 0x0d, 0xdd, 0x28, 0x65, 0xdb, 0x9e, 0x26, 0x0a, 0xb7,
+  0x5d, 0x1c, 0xe6, 0x0d, 0xcf, 0x30, 0x7e, 0x6e, 0x8e, 0xcb, 0x55, 0x9d,
+  0xdb, 0x1a, 0xeb, 0x98, 0xe7, 0xd2, 0x05, 0xb4, 0x55, 0xa0, 0xac, 0xbf,
+  0x14, 0x1f, 0xc7, 0x03, 0xb1, 0xd1, 0x06, 0x82, 0x90, 0xd6, 0xdb, 0xe6,
+  0x7b, 0xd4, 0xcd, 0xa3, 0xc7, 0x4d, 0x31, 0xf1, 0x35, 0x6a, 0xa2, 0xc8,
+  0x01, 0xe0, 0x65, 0x03, 0x13, 0x58, 0x86, 0x29, 0x5b, 0xd3, 0x81, 0xe0,
+  0xcf, 0x86, 0x98, 0x4e, 0x47, 0xc8, 0x25, 0x16, 0xc5, 0x89, 0x34, 0x80,
+  0xe4, 0x68, 0x14, 0x02, 0xeb, 0xd3, 0x08, 0xa6, 0xf0, 0x0e, 0xa2, 0x2f,
+  0xd3, 0xd1, 0xee, 0xef, 0x61, 0x6d, 0xd9, 0xb2, 0xa6, 0x30, 0x4d, 0xf8,
+  0x7b, 0x05, 0xbe, 0x41, 0x1e, 0x7e, 0x12, 0xe3, 0x87, 0x15, 0xe7, 0x36,
+  0xec, 0x21, 0x51, 0x94, 0x07, 0xbe, 0xbe, 0x45, 0x77, 0x01, 0x20, 0x6a,
+  0x48, 0xa5, 0x81, 0x46, 0xcf, 0x61, 0xf5, 0xcb, 0xf4, 0xff, 0x6b, 0x16,
+  0x86, 0x2a, 0xaa, 0xb3, 0x75, 0x00, 0x47, 0x32, 0x86, 0x6b, 0x40, 0x8d,
+  0x05, 0x5b, 0x66, 0x42, 0x81, 0x56, 0xe4, 0xb0, 0xeb, 0x6b, 0x91, 0x4d,
+  0xe7, 0xaf, 0x75, 0x36, 0x29, 0x01, 0xb3, 0x52, 0xc1, 0x08, 0xa1, 0x62,
+  0x8c, 0x26, 0xa5, 0xaa, 0x9b, 0x65, 0x20, 0x68, 0x83, 0x84, 0xf2, 0xac,
+  0x72, 0x64, 0xea, 0x85, 0x1d, 0x41, 0xcc, 0x9b, 0x47, 0xc5, 0xa8, 0xec,
+  0xf7, 0x39, 0x13, 0xb7, 0x25, 0x8b, 0xf7, 0x4d, 0x31, 0x45, 0xe4, 0xfc,
+  0x89, 0x49, 0x02, 0x8f, 0x81, 0x03, 0x01, 0x77, 0xf3, 0x58, 0xa0, 0x27,
+  0xc0, 0x7a, 0x03, 0x41, 0x18, 0x02, 0xd0, 0x11, 0xfc, 0xb2, 0x08, 0x5c,
+  0xbc, 0x02, 0x42, 0x15, 0xa4, 0xf7, 0xb3, 0x29, 0xde, 0x75, 0x78, 0x29,
+  0x70, 0x29, 0x90, 0xe7, 0xe3, 0x99, 0xf9, 0x23, 0xcc, 0xfc, 0x2e, 0xb3,
+  0xef, 0xf3, 0x4d, 0x9c, 0x47, 0x51, 0x1f, 0x3b, 0xcc, 0xc4, 0x7a, 0x75,
+  0x59, 0x4d, 0xb5, 0x48, 0xa2, 0x7e, 0x6e, 0xea, 0x0d, 0xf2, 0x2a, 0x10,
+  0xfc, 0x87, 0x24, 0xfe, 0x04, 0xfa, 0x79, 0x2c, 0x79, 0xd8, 0xcf, 0x40,
+  0xbf, 0x32, 0x38, 0x3c, 0xe1, 0x59, 0x97, 0x03, 0x8c, 0x6c, 0x8d, 0xda,
+  0x6f, 0xf2, 0xe0, 0x31, 0xaf, 0x6c, 0x8c, 0xc7, 0x76, 0x99, 0xa0, 0xf6,
+  0x33, 0x6a, 0xb4, 0xe8, 0x68, 0xef, 0x25, 0x8f, 0x1d, 0x02, 0xa9, 0x3a,
+  0xb4, 0x3a, 0xb7, 0xce, 0x3e, 0x45, 0x00, 0x7a, 0x73, 0x48, 0x00, 0xb3,
+  0x45, 0x57, 0x00, 0x7a, 0xd3, 0x11, 0x20, 0x83, 0x75, 0xc7, 0x93, 0x23,
+  0x76, 0x82, 0xf9, 0x63, 0x19, 0x3e, 0x27, 0x6a, 0x6f, 0xa8, 0xe4, 0x5c,
+  0xc2, 0x9a, 0x7e, 0xda, 0xdb, 0x44, 0xa7, 0x9f, 0x56, 0x09, 0x6a, 0x53,
+  0x43, 0x54, 0xf5, 0x62, 0x46, 0xca, 0x26, 0x00, 0x70, 0x5a, 0x14, 0xe6,
+  0x97, 0xd0, 0xf6, 0x57, 0x40, 0xc8, 0xe0, 0xc3, 0xbf, 0x84, 0x1c, 0x65,
+  0x71, 0x90, 0xc1, 0x10, 0xf9, 0x3f, 0x75, 0x89, 0xc3, 0x22, 0xab, 0x1a,
+  0x7d, 0xb4, 0x33, 0x49, 0xba, 0xcd, 0xfa, 0x67, 0x44, 0xbd, 0xe9, 0x22,
+  0x84, 0x17, 0x84, 0x74, 0xab, 0xd2, 0x08, 0x3d, 0x0d, 0x45, 0x74, 0xd3,
+  0x8c, 0x68, 0x8c, 0x58, 0x67, 0xb5, 0xe4, 0x15, 0x7b, 0xcc, 0xee, 0x0a,
+  0xd8, 0x05, 0xe8, 0xe6, 0x2b, 0xa2, 0xf1, 0xa6, 0x0e, 0x6e, 0xca, 0x90,
+  0xa6, 0x5a, 0xbe, 0xa6, 0x89, 0x50, 0xe1, 0xab, 0x0a, 0x3e, 0x67, 0x00,
+  0xa4, 0xe9, 0xea, 0x2f, 0x0c, 0xd1, 0xdb, 0x08, 0x37, 0xc9, 0x96, 0x6d,
+  0xf2, 0x18, 0x20, 0xd5, 0x59, 0xba, 0x69, 0x85, 0x98, 0x83, 0xd1, 0xb8,
+  0x7a, 0x61, 0x92, 0xad, 0x56, 0x8a, 0x81, 0xa8, 0x90, 0x45, 0xa9, 0xbc,
+  0xb5, 0x75, 0xf7, 0x03, 0xab, 0xc2, 0x48, 0x40, 0xd8, 0x0d, 0x03, 0x09,
+  0xfc, 0xd9, 0x3e, 0xae, 0xa6, 0xa2, 0x15, 0x11, 0x1a, 0x21, 0x3d, 0x09,
+  0x19, 0x13, 0xfd, 0x2a, 0x52, 0xe3, 0x6c, 0x0c, 0x57, 0x07, 0xfa, 0x93,
+  0x65, 0xf0, 0x18, 0x57, 0x57, 0x2d, 0xd2, 0x48, 0xa0, 0x0f, 0xcd, 0x26,
+  0x4b, 0xbd, 0x13, 0x54, 0x48, 0xa5, 0x7e, 0x68, 0x71, 0xa5, 0xb1, 0xe1,
+  0x2b, 0x45, 0x15, 0xda, 0xc8, 0x00, 0x3a, 0xc2, 0x7f, 0xc6, 0xef, 0x67,
+  0x54, 0xe6, 0x51, 0xf7, 0x61, 0xae, 0xf6, 0x6b, 0x1b, 0x0f, 0x0b, 0xc8,
+  0x6f, 0x3f, 0x1e, 0xfe, 0x0d, 0xdf, 0x84, 0x36, 0xef, 0x4a, 0x9c, 0xa5,
+  0x2c, 0x92, 0xeb, 0x49, 0x15, 0x8a, 0xa8, 0x51, 0xdf, 0xdf, 0x4b, 0x98,
+  0xa8, 0x10, 0xb7, 0xd5, 0x30, 0xe8, 0xb2, 0x88, 0x53, 0x2b, 0x07, 0xba,
+  0xeb, 0x4e, 0xf0, 0x05, 0x47, 0x58, 0x87, 0x08, 0x13, 0x23, 0xb3, 0x69,
+  0xea, 0x6d, 0x24, 0xb3, 0xf9, 0xff, 0xa2, 0x37, 0x35, 0xea, 0x5f, 0xa2,
+  0x37, 0xf4, 0xa2, 0xef, 0x94, 0x77, 0xe1, 0x1c, 0x60, 0xf0, 0x63, 0xc6,
+  0xa5, 0x29, 0xdd, 0x16, 0xeb, 0x6d, 0xa5, 0x64, 0xd5, 0x44, 0xe7, 0x26,
+  0x65, 0x23, 0xc1, 0x76, 0x3c, 0xc9, 0xab, 0xad, 0xf4, 0x32, 0x28, 0x04,
+  0xe7, 0xf9, 0xae, 0x8c, 0xa7, 0x4e, 0x42, 0x2a, 0xc4, 0x82, 0x7c, 0xf8,
+  0x27, 0xcc, 0x86, 0x50, 0xd1, 0xe8, 0xa4, 0xce, 0x76, 0xea, 0x08, 0xa9,
+  0x72, 0x81, 0x2e, 0x97, 0x58, 0x65, 0x22, 0x1d, 0x0e, 0x45, 0x5b, 0xed,
+  0x1f, 0x82, 0x91, 0x13, 0x81, 0x8c, 0x06, 0x18, 0x34, 0x63, 0xcb, 0x46,
+  0xee, 0xd1, 0xc8, 0x31, 0x8a, 0xdc, 0xa1, 0xf1, 0x73, 0xd2, 0x8a, 0xec,
+  0x35, 0x70, 0xab, 0x6d, 0x25, 0xa3, 0x39, 0xa0, 0x50, 0x02, 0x23, 0x5c,
+  0xa1, 0x8b, 0xab, 0x2f, 0x13, 0x23, 0x1d, 0xd1, 0x59, 0x99, 0x5b, 0xe5,
+  0x1c, 0x7a, 0x63, 0xec, 0x98, 0x7d, 0x2c, 0xf7, 0x5d, 0xe6, 0xde, 0xb1,
+  0x54, 0x26, 0x0e, 0x2c, 0x9f, 0xad, 0xcd, 0xac, 0xf1, 0xfe, 0xab, 0xc9,
+  0x8e, 0x0a, 0xe2, 0xe5, 0x02, 0x10, 0x48, 0x86, 0xb7, 0xaf, 0x21, 0x33,
+  0xe3, 0xf1, 0x96, 0x9c, 0x93, 0x18, 0xf2, 0xf5, 0x85, 0x7e, 0x09, 0x88,
+  0xa7, 0xd2, 0x71, 0x78, 0xfa, 0x70, 0x6a, 0x61, 0x35, 0xe3, 0x27, 0xe5,
+  0x40, 0x75, 0x31, 0xe3, 0x67, 0x6b, 0xe6, 0x50, 0x29, 0xc5, 0xb4, 0x4a,
+  0x68, 0x66, 0xba, 0xaf, 0xce, 0xf4, 0xa0, 0xa3, 0x9f, 0x78, 0x39, 0x56,
+  0x29, 0x1c, 0x88, 0xc1, 0x2f, 0x75, 0xc1, 0xe2, 0xc9, 0xed, 0x73, 0x7f,
+  0x5a, 0xe6, 0x82, 0xd0, 0x7e, 0x02, 0x10, 0xaf, 0x00, 0x99, 0x08, 0x96,
+  0x31, 0xf9, 0x44, 0xa1, 0x61, 0x36, 0x9d, 0x15, 0x7b, 0xb4, 0x7c, 0x33,
+  0xed, 0x8a, 0x79, 0xae, 0x05, 0x9d, 0x41, 0x17, 0x99, 0x8b, 0x78, 0xa1,
+  0xd5, 0xa9, 0x69, 0x3a, 0x29, 0xcd, 0x33, 0x36, 0x9d, 0x2d, 0xcc, 0x67,
+  0xa0, 0xae, 0x6a, 0x70, 0xd5, 0xbc, 0xb6, 0xe9, 0x3a, 0x90, 0xee, 0x16,
+  0xfc, 0xab, 0x69, 0x76, 0x04, 0x8b, 0x92, 0x1d, 0x7b, 0x8a, 0xd6, 0x36,
+  0xb5, 0x0c, 0x5c, 0x9f, 0xd5, 0x4d, 0xa8, 0xef, 0x1b, 0xef, 0xb3, 0xdc,
+  0xf3, 0x58, 0x96, 0x99, 0x0d, 0x1a, 0x34, 0x2a, 0xae, 0x76, 0x98, 0x50,
+  0xac, 0x3f, 0x98, 0x2d, 0x37, 0x90, 0x7d, 0x4f, 0x2d, 0x3e, 0xd8, 0x59,
+  0x2f, 0xb4, 0xb8, 0xb5, 0xd8, 0xcb, 0x5f, 0xbd, 0xc6, 0x21, 0x5b, 0xc5,
+  0xf0, 0x3b, 0x43, 0x54, 0x5b, 0x31, 0x7a, 0x8c, 0x0e, 0x1a, 0xc5, 0xd4,
+  0xba, 0x58, 0x67, 0xb0, 0x24, 0x85, 0xb0, 0xd4, 0x0d, 0xf4, 0xa9, 0x14,
+  0xb7, 0x37, 0xe6, 0x0a, 0x99, 0x4c, 0xf5, 0xee, 0xc3, 0x29, 0xa9, 0xf5,
+  0x76, 0x4a, 0x0c, 0x06, 0xc6, 0x14, 0xd6, 0x2d, 0x9c, 0x80, 0x66, 0xc3,
+  0x9a, 0x98, 0x19, 0x3b, 0xf3, 0xe5, 0xdc, 0xea, 0x7d, 0xf7, 0x89, 0x7e,
+  0x64, 0x5a, 0xc8, 0x62, 0x70, 0xd6, 0x4b, 0xb6, 0x5a, 0x40, 0x93, 0x28,
+  0x5a, 0x27, 0x96, 0x0f, 0xd0, 0xa6, 0x0d, 0xa1, 0xc0, 0x3c, 0xa7, 0x67,
+  0x33, 0x20, 0x82, 0x02, 0xbd, 0xc2, 0x52, 0x58, 0xfd, 0x75, 0x56, 0x57,
+  0x04, 0x80, 0x24, 0x74, 0x36, 0x86, 0x50, 0x44, 0x8a, 0x0d, 0xb9, 0xf1,
+  0xdf, 0x43, 0x07, 0x43, 0x8d, 0xa9, 0xe5, 0x46, 0x54, 0x5c, 0xd9, 0xaa,
+  0xcd, 0x29, 0xb9, 0xe7, 0xfe, 0x1c, 0x99, 0x63, 0x89, 0x88, 0xc2, 0x64,
+  0x41, 0xbf, 0x53, 0xe5, 0x66, 0xf3, 0xda, 0xd9, 0xf6, 0xa7, 0xa4, 0xd4,
+  0xf1, 0xb4, 0x4d, 0x54, 0x69, 0x00, 0xba, 0x37, 0x2c, 0xa2, 0x94, 0x60,
+  0xd1, 0x68, 0xba, 0x37, 0x7e, 0x1b, 0x90, 0xd6, 0x33, 0xb5, 0xf7, 0xb5,
+  0x16, 0xe6, 0x43, 0x13, 0x39, 0x37, 0x7f, 0xec, 0x8d, 0x12, 0x9c, 0xe9,
+  0x5c, 0x86, 0x36, 0xf5, 0xf8, 0x76, 0x09, 0xf7, 0x18, 0x26, 0xc1, 0xa6,
+  0x70, 0x45, 0x17, 0x9c, 0x70, 0x9c, 0xac, 0x7a, 0xb2, 0x3b, 0x76, 0xf9,
+  0x06, 0xac, 0x3c, 0xad, 0xad, 0x12, 0xff, 0xf5, 0xf8, 0x67, 0xcb, 0x01,
+  0xc6, 0x9c, 0xab, 0xdf, 0x9d, 0xbb, 0xee, 0xda, 0xe3, 0xd3, 0x1d, 0x9f,
+  0x1f, 0x70, 0xd6, 0x01, 0x87, 0xd5, 0x1e, 0x39, 0xc4, 0xc0, 0xf0, 0xe9,
+  0xc2, 0xd8, 0x09, 0x0b, 0x33, 0xd6, 0xf0, 0x84, 0x0d, 0x58, 0x7e, 0x50,
+  0x5a, 0xf7, 0x1e, 0x3d, 0xb1, 0x50, 0x24, 0x90, 0xb9, 0xdc, 0xc1, 0x88,
+  0x5f, 0xa8, 0x5d, 0x31, 0x06, 0x50, 0x0c, 0xab, 0x18, 0x43, 0x6b, 0xeb,
+  0x98, 0x11, 0xd6, 0x4e, 0x3a, 0x40, 0xd8, 0xf6, 0x28, 0x34, 0x9c, 0x61,
+  0xda, 0x1e, 0x8d, 0xd5, 0x58, 0x86, 0xa8, 0x2b, 0x54, 0x28, 0xa4, 0xec,
+  0x03, 0xee, 0x07, 0x15, 0x70, 0xdf, 0x0f, 0xda, 0xa4, 0x81, 0xc2, 0x07,
+  0x81, 0xb3, 0xd2, 0x07, 0x4a, 0x0d, 0x8b, 0xd4, 0x6f, 0xaa, 0x75, 0xc8,
+  0x01, 0xd1, 0x2c, 0x2f, 0xe4, 0xde, 0x15, 0x08, 0x6b, 0xe9, 0x3a, 0x6b,
+  0x65, 0x90, 0x36, 0xce, 0x29, 0xb2, 0x31, 0x07, 0xc2, 0x4a, 0xa1, 0x14,
+  0x44, 0xd4, 0xe6, 0xa8, 0xa0, 0x42, 0x8f, 0xae, 0x17, 0x1d, 0x0a, 0x33,
+  0xe8, 0x63, 0xa1, 0xf7, 0xbd, 0x0d, 0x9c, 0x9a, 0x6a, 0x4a, 0xb3, 0xda,
+  0x74, 0x0c, 0x2c, 0x31, 0x4c, 0xe5, 0xb4, 0xb6, 0x95, 0xa2, 0x87, 0xf1,
+  0xc4, 0x44, 0xd8, 0xc5, 0xe1, 0xa1, 0xab, 0x8a, 0x69, 0x43, 0xae, 0xde,
+  0x51, 0x8f, 0x8f, 0x31, 0x56, 0x2e, 0x59, 0x55, 0x5f, 0x81, 0x89, 0x4c,
+  0xb6, 0xdb, 0xb0, 0x02, 0x5c, 0xc3, 0x42, 0x3f, 0x65, 0x2e, 0xb4, 0xa2,
+  0x86, 0x26, 0xa3, 0x36, 0x88, 0x5f, 0x30, 0x17, 0x6a, 0xba, 0xcd, 0xa9,
+  0x30, 0xcc, 0xec, 0xf8, 0xd9, 0x30, 0x00, 0xb2, 0x33, 0x45, 0xe5, 0xd8,
+  0x5d, 0x97, 0xbc, 0xe0, 0x3b, 0x46, 0xf2, 0x14, 0x37, 0x88, 0xb2, 0x39,
+  0xd6, 0x4e, 0xc1, 0x60, 0xc1, 0x43, 0x18, 0x09, 0xc1, 0x95, 0xc8, 0x35,
+  0x64, 0xc6, 0x84, 0x92, 0x4a, 0x3e, 0x3c, 0x28, 0x03, 0xf8, 0x92, 0x9d,
+  0x12, 0x48, 0x05, 0x31, 0x39, 0x46, 0x12, 0xd7, 0x01, 0x87, 0x2c, 0x24,
+  0x81, 0x84, 0x98, 0x67, 0x4a, 0x60, 0xf4, 0x1f, 0x08, 0xcb, 0xd7, 0x58,
+  0x3f, 0x23, 0x38, 0x11, 0xcc, 0x77, 0x54, 0xb0, 0x55, 0xe8, 0xfe, 0x35,
+  0xd6, 0x2c, 0x15, 0xa4, 0x6f, 0x28, 0xc0, 0x47, 0x33, 0xb0, 0x2a, 0x66,
+  0xa7, 0x5e, 0x90, 0x1f, 0x34, 0x7b, 0xcd, 0x1a, 0xab, 0xed, 0x98, 0xeb,
+  0x92, 0x7f, 0xfc, 0x03, 0xc3, 0x41, 0xd3, 0x14, 0x36, 0x1c, 0xc3, 0xf7,
+  0x69, 0xef, 0xba, 0xa5, 0xc9, 0x96, 0x2a, 0x72, 0x3c, 0x54, 0x6d, 0x51,
+  0xb5, 0xb0, 0x66, 0x40, 0x94, 0x58, 0xb8, 0xa7, 0x6f, 0x15, 0x8e, 0x3c,
+  0x03, 0xe7, 0x8f, 0xb7, 0x32, 0x20, 0x2b, 0x72, 0x76, 0x30, 0x14, 0xe8,
+  0x1f, 0xb5, 0x5e, 0x7d, 0x71, 0x3d, 0x0f, 0x13, 0x4f, 0x6d, 0x3a, 0x39,
+  0x98, 0x15, 0x18, 0xb6, 0x85, 0xc7, 0x5a, 0x4c, 0xe2, 0x4d, 0x5b, 0xc5,
+  0xb7, 0xe0, 0xa6, 0x16, 0x46, 0xf4, 0xab, 0xf2, 0xb7, 0xea, 0x52, 0x98,
+  0x49, 0xbb, 0xed, 0xac, 0x44, 0xa1, 0xd9, 0xe2, 0x28, 0x5c, 0x2e, 0x04,
+  0x47, 0x4b, 0x01, 0x3d, 0x5f, 0x03, 0x4d, 0x58, 0xd2, 0x55, 0xdb, 0x37,
+  0x49, 0x2e, 0xbc, 0x86, 0xe5, 0xb3, 0x9d, 0x3a, 0xe1, 0x03, 0x9a, 0x67,
+  0xd7, 0xc4, 0x68, 0x03, 0x81, 0x85, 0x7e, 0x55, 0x5a, 0xb4, 0x0a, 0xd8,
+  0x95, 0x15, 0xf9, 0xa8, 0x8a, 0x8e, 0x1b, 0xf5, 0x6a, 0x4c, 0x8a, 0xdc,
+  0x38, 0xed, 0xd0, 0x5e, 0x64, 0xf4, 0x87, 0x66, 0xd3, 0x33, 0x1c, 0x78,
+  0xa2, 0x86, 0x53, 0x70, 0x9d, 0x1d, 0x68, 0x5e, 0x34, 0x3b, 0xbe, 0x43,
+  0x9a, 0x8b, 0x76, 0x7b, 0xcc, 0xd4, 0xaa, 0xf4, 0xc6, 0x2d, 0xf3, 0x57,
+  0x7d, 0xb0, 0xcd, 0x48, 0x5f, 0xcb, 0x43, 0x70, 0x68, 0x9c, 0x45, 0x89,
+  0x12, 0xf2, 0xf5, 0xba, 0x6e, 0xf2, 0x3d, 0xc4, 0x4b, 0x75, 0xed, 0xa2,
+  0xda, 0x0e, 0x54, 0xfd, 0x09, 0x86, 0x2e, 0x36, 0x26, 0x72, 0x90, 0x44,
+  0x3e, 0xf7, 0xeb, 0x92, 0xdf, 0x52, 0xfa, 0x55, 0x62, 0x8b, 0xb6, 0x6b,
+  0x24, 0x82, 0xcd, 0xed, 0x9f, 0x01, 0xe8, 0x21, 0xe5, 0xb6, 0x50, 0x19,
+  0x6f, 0x74, 0x22, 0x3b, 0xc5, 0xb9, 0xea, 0xa8, 0x04, 0xf8, 0xa1, 0xfc,
+  0xf5, 0x9a, 0xca, 0xa0, 0x55, 0x7e, 0x6c, 0x6d, 0xbe, 0x69, 0xa1, 0x70,
+  0xeb, 0xcd, 0xc8, 0x50, 0x9b, 0x95, 0x45, 0x48, 0xf5, 0xfd, 0x7a, 0x53,
+  0xa1, 0x1a, 0x8e, 0xda, 0xc5, 0xc6, 0xea, 0xcb, 0x33, 0xf8, 0xa2, 0x76,
+  0x09, 0xa9, 0x24, 0xd6, 0xd9, 0x63, 0xe7, 0xec, 0x91, 0xf3, 0xf8, 0xec,
+  0xec, 0x1b, 0xf2, 0xe8, 0xeb, 0xf9, 0xd9, 0x57, 0x16, 0x96, 0x65, 0xfa,
+  0xfb, 0x97, 0x7b, 0xbb, 0x8a, 0x46, 0xf1, 0x63, 0xb0, 0x75, 0x51, 0x33,
+  0x30, 0xeb, 0xd8, 0xce, 0x53, 0xf5, 0xac, 0x51, 0x94, 0x2e, 0xf6, 0x31,
+  0xde, 0xd2, 0x6d, 0x06, 0xa4, 0x54, 0x61, 0xb2, 0x20, 0x10, 0xd2, 0x35,
+  0x0b, 0x15, 0x2f, 0xe4, 0xa1, 0x0a, 0x90, 0xa4, 0xbf, 0xa8, 0xfd, 0x0a,
+  0x32, 0x6d, 0xec, 0xbd, 0x86, 0xbc, 0x5b, 0xed, 0x8c, 0x62, 0xa7, 0xf2,
+  0xa1, 0x5b, 0x3e, 0x69, 0xd6, 0xb1, 0x7b, 0x65, 0xae, 0x0e, 0x69, 0x59,
+  0x65, 0xdd, 0xb5, 0x7b, 0x06, 0x6a, 0xd8, 0x20, 0x70, 0xe2, 0x07, 0x97,
+  0xbc, 0x5f, 0x6a, 0x05, 0x55, 0xf2, 0xf6, 0xb1, 0xb3, 0x5e, 0x9c, 0x86,
+  0x42, 0x45, 0xd6, 0xbb, 0x44, 0xe5, 0x43, 0xe2, 0x98, 0xf3, 0x57, 0x6e,
+  0x39, 0x29, 0x37, 0x2a, 0xfb, 0x57, 0xf0, 0x3f, 0x50, 0x8a, 0x6e, 0xb0,
+  0x4c, 0x42, 0x9c, 0xa7, 0x73, 0xeb, 0x1b, 0x53, 0x4b, 0x66, 0x09, 0xcf,
+  0xa8, 0xde, 0x0d, 0x96, 0xf0, 0x91, 0x63, 0xcb, 0x03, 0x06, 0x77, 0x6b,
+  0x79, 0xa3, 0x88, 0x64, 0xfa, 0xf1, 0x67, 0x28, 0xfc, 0x2f, 0xab, 0xa3,
+  0x67, 0xc5, 0x3e, 0x2c, 0x9a, 0x94, 0x85, 0x99, 0x19, 0x8c, 0xe4, 0x71,
+  0x6b, 0x57, 0x1d, 0xec, 0x8d, 0xdf, 0x59, 0x0d, 0xcb, 0x2b, 0xbb, 0x0f,
+  0x4a, 0xa4, 0x77, 0xf6, 0x7b, 0x77, 0xf1, 0xdf, 0x25, 0x3c, 0xd6, 0x6e,
+  0x41, 0x30, 0x96, 0x1a, 0xdb, 0xc4, 0x3f, 0x25, 0xe0, 0x99, 0x5e, 0x40,
+  0xfe, 0x92, 0x44, 0x11, 0xcd, 0x08, 0xac, 0x88, 0xb0, 0xa0, 0xb7, 0xeb,
+  0xe6, 0xfd, 0x5b, 0xd7, 0xa9, 0xe0, 0x90, 0xb7, 0xde, 0xb6, 0xf7, 0xae,
+  0xdf, 0xa4, 0x8c, 0x7b, 0x01, 0x13, 0x3d, 0x67, 0x16, 0x9a, 0x27, 0x16,
+  0xda, 0xb6, 0xd3, 0x03, 0xce, 0xa5, 0xa9, 0xe1, 0xf6, 0x5b, 0x57, 0x88,
+  0x9e, 0x98, 0xb5, 0x90, 0x45, 0xb7, 0xad, 0xaa, 0x66, 0x4a, 0xbd, 0x24,
+  0x8f, 0x7d, 0xd2, 0x29, 0x5f, 0xb4, 0xf0, 0x91, 0xc5, 0x1e, 0x64, 0xa2,
+  0x1a, 0x21, 0x6b, 0xf9, 0x35, 0x52, 0xe8, 0x15, 0x61, 0xbc, 0x56, 0xa0,
+  0x12, 0xf9, 0x62, 0xb1, 0x38, 0x50, 0x2b, 0x18, 0xb0, 0xbe, 0xcf, 0x55,
+  0x5d, 0x18, 0x3a, 0x64, 0x12, 0x66, 0xa0, 0x88, 0xa2, 0x3c, 0x85, 0x27,
+  0x17, 0xaf, 0x38, 0xd6, 0x17, 0x3a, 0x65, 0x1a, 0x76, 0xcb, 0x6c, 0x2f,
+  0x4c, 0x32, 0xa6, 0x95, 0x31, 0x58, 0x7f, 0x28, 0x74, 0xf3, 0x06, 0x6c,
+  0x55, 0xb0, 0x83, 0xea, 0xc9, 0xe3, 0x0c, 0x1a, 0x7e, 0x99, 0x9a, 0x79,
+  0xa9, 0x75, 0x91, 0x63, 0xdc, 0x1c, 0xcb, 0x0d, 0x18, 0x70, 0x8f, 0x5a,
+  0xb4, 0xf8, 0x83, 0x1a, 0x81, 0xd8, 0xf2, 0x90, 0x0a, 0xbe, 0x78, 0x05,
+  0x0c, 0x8c, 0xda, 0x56, 0x65, 0xb0, 0xe3, 0xac, 0xe1, 0x19, 0xac, 0x35,
+  0xf2, 0xb0, 0x35, 0x40, 0x20, 0x29, 0x13, 0xc1, 0xbe, 0x2c, 0x6d, 0xac,
+  0xfe, 0xc6, 0x19, 0x80, 0x24, 0x00, 0x18, 0xc4, 0x98, 0x10, 0x71, 0xf4,
+  0x94, 0xde, 0x46, 0x07, 0xa5, 0x22, 0xd2, 0xff, 0xe3, 0x21, 0x8d, 0x54,
+  0x16, 0x2f, 0x63, 0x7f, 0xfb, 0xe1, 0xdf, 0xa1, 0xe4, 0x5b, 0x12, 0x7e,
+  0xf8, 0x57, 0x36, 0x54, 0x7e, 0x2d, 0x2c, 0x59, 0x4f, 0xa7, 0x3e, 0xf3,
+  0x78, 0x8c, 0x77, 0x97, 0x07, 0x31, 0xbf, 0xac, 0x19, 0xfd, 0x31, 0x17,
+  0x1f, 0xfe, 0xed, 0x5d, 0x85, 0x6c, 0x3b, 0x3a, 0xd8, 0xd1, 0xb1, 0x7d,
+  0x99, 0x23, 0xd3, 0x33, 0xc3, 0x47, 0x2b, 0xc4, 0xe3, 0xe3, 0xf2, 0x95,
+  0xab, 0x7e, 0xb9, 0xf6, 0xfa, 0x62, 0xcc, 0x48, 0x0b, 0xc8, 0xe9, 0x84,
+  0x0d, 0x61, 0xb2, 0xe5, 0xf1, 0xaf, 0xe9, 0x62, 0x13, 0x39, 0x89, 0x7d,
+  0x9a, 0x05, 0x0b, 0x72, 0x11, 0x97, 0xe7, 0x3d, 0x07, 0x6e, 0x3a, 0x7d,
+  0xfc, 0xe5, 0xa4, 0xff, 0xea, 0x85, 0x9b, 0xea, 0x1e, 0x85, 0x9a, 0x02,
+  0xa3, 0x07, 0x9e, 0xef, 0xa9, 0xee, 0x5d, 0x34, 0x6e, 0xe8, 0x54, 0xb7,
+  0x27, 0x08, 0xcd, 0x65, 0x32, 0x70, 0x85, 0xe2, 0x0b, 0xb8, 0x41, 0x71,
+  0xf8, 0x7c, 0xbf, 0xd7, 0x39, 0x4d, 0xdb, 0x3d, 0x81, 0xa8, 0x4d, 0x73,
+  0xe4, 0x00, 0x32, 0x8f, 0xcd, 0xf4, 0xa7, 0x3c, 0x84, 0xdb, 0x38, 0xbc,
+  0x6f, 0x1e, 0xa4, 0xd5, 0x08, 0x7d, 0x29, 0x44, 0x02, 0xa9, 0xb1, 0x29,
+  0x0c, 0x85, 0x64, 0x59, 0x12, 0xf5, 0xbf, 0xcd, 0xf0, 0xb5, 0x4a, 0x2f,
+  0xca, 0x86, 0xaa, 0xa4, 0xdc, 0x4e, 0x88, 0x47, 0xdc, 0x59, 0x15, 0xd0,
+  0x0d, 0x77, 0x7e, 0x05, 0xbf, 0x1b, 0x09, 0xcf, 0x52, 0x65, 0xdc, 0x78,
+  0xc0, 0x12, 0x1c, 0x27, 0x43, 0x15, 0x3c, 0x61, 0x71, 0x2e, 0xef, 0x20,
+  0x8c, 0x02, 0x02, 0x4b, 0x57, 0xbd, 0x5e, 0x4d, 0x46, 0x32, 0x19, 0xdc,
+  0x4e, 0x51, 0x3d, 0xcb, 0x63, 0x71, 0x59, 0xf3, 0x44, 0xa1, 0x3e, 0x11,
+  0x6d, 0x29, 0x2b, 0xd9, 0x24, 0x5e, 0x9e, 0xf5, 0x33, 0x4f, 0x61, 0xf4,
+  0xa0, 0x28, 0x1f, 0x0f, 0xd1, 0xa8, 0x6f, 0x72, 0x9c, 0x79, 0xd5, 0x5e,
+  0x1f, 0x3e, 0xae, 0x7e, 0x69, 0x21, 0x6a, 0x6a, 0x46, 0xe2, 0x2e, 0x46,
+  0xb3, 0xf8, 0x91, 0xd4, 0xa8, 0xf6, 0xee, 0x76, 0xf6, 0x5d, 0xe7, 0x45,
+  0xc6, 0xe1, 0xe3, 0x83, 0x7b, 0x57, 0xc5, 0x99, 0xf8, 0x5f, 0x25, 0xd0,
+  0x19, 0xc7, 0xf8, 0x7f, 0xe5, 0x50, 0xa7, 0x67, 0xa1, 0x7d, 0x63, 0x6c,
+  0xf0, 0xbe, 0xd8, 0xa1, 0xdb, 0x62, 0xbf, 0x0a, 0xa8, 0x1b, 0xbb, 0xca,
+  0xd4, 0xdf, 0xb6, 0x71, 0x9d, 0xe9, 0x30, 0x72, 0x7e, 0xc2, 0x85, 0x26,
+  0xa3, 0x63, 0xe3, 0x70, 0x7c, 0xfb, 0x3e, 0x46, 0xe3, 0x5a, 0xc9, 0x81,
+  0xdb, 0x46, 0xe5, 0x4d, 0x8a, 0xe6, 0x11, 0xf6, 0x8f, 0xbc, 0x96, 0xd2,
+  0xbc, 0xfa, 0x72, 0xec, 0xf5, 0x97, 0x9e, 0x0b, 0x2d, 0x9f, 0xef, 0x52,
+  0xcb, 0xe7, 0xb8, 0xd8, 0xf2, 0x1f, 0xbd, 0xdc, 0xd2, 0x77, 0xc1, 0xa5,
+  0x7b, 0xc9, 0xa5, 0xad, 0xd9, 0xe6, 0x71, 0xaa, 0xfa, 0xc7, 0xc1, 0x1b,
+  0x1c, 0xb5, 0xe1, 0xe2, 0x8a, 0x6b, 0xcc, 0x65, 0xbd, 0xd6, 0x2e, 0xd3,
+  0xd5, 0x4b, 0x2e, 0x09, 0x9e, 0x3e, 0x8d, 0x6a, 0xf8, 0x22, 0x57, 0x27,
+  0x49, 0x0e, 0x20, 0x08, 0xc6, 0x1d, 0x93, 0xd7, 0x22, 0xd9, 0x0a, 0x1a,
+  0x45, 0x0c, 0xd6, 0xdd, 0x8c, 0x18, 0x03, 0xcd, 0x37, 0x80, 0x07, 0xac,
+  0x12, 0xd3, 0x3c, 0xbc, 0x7a, 0x97, 0x6f, 0x05, 0xe3, 0x1b, 0x16, 0x9f,
+  0x92, 0x3b, 0xe7, 0x24, 0x5e, 0x67, 0xe9, 0xe2, 0x89, 0x43, 0x40, 0x75,
+  0xc4, 0x67, 0x51, 0xd5, 0xe3, 0xcf, 0xe0, 0x8f, 0x80, 0x9e, 0x4b, 0xbc,
+  0x7f, 0xbd, 0xba, 0x50, 0x97, 0xd8, 0xf9, 0x9d, 0xda, 0x43, 0x9f, 0x93,
+  0x27, 0x8c, 0x0a, 0x44, 0xd6, 0x50, 0x2e, 0x94, 0x48, 0x27, 0x5b, 0xb9,
+  0x58, 0xba, 0xaa, 0xa5, 0x3a, 0x11, 0x5b, 0x1e, 0x9f, 0xf8, 0x7c, 0xa1,
+  0x42, 0x75, 0xd2, 0x4e, 0x1f, 0x46, 0xd9, 0xf7, 0x38, 0xbd, 0x49, 0xb3,
+  0x2c, 0x66, 0xd4, 0xf3, 0x84, 0xe7, 0xed, 0x32, 0xa2, 0x15, 0x78, 0xb2,
+  0xf6, 0xdf, 0xe7, 0xc9, 0x42, 0x1f, 0xc7, 0x53, 0x8f, 0xf4, 0x5d, 0x8f,
+  0x93, 0x50, 0x3d, 0x77, 0xc8, 0x65, 0x46, 0xae, 0xb9, 0xf0, 0x49, 0x9c,
+  0x0b, 0xf2, 0x8e, 0xc9, 0x3b, 0x20, 0x0b, 0x99, 0xc2, 0x1d, 0xe3, 0x5b,
+  0x39, 0xaf, 0x67, 0x3e, 0x15, 0x6c, 0x65, 0xd0, 0x40, 0xc1, 0xf1, 0xd1,
+  0xa4, 0xc7, 0x30, 0x5a, 0x67, 0xf8, 0xf4, 0x68, 0xf4, 0x84, 0xd7, 0x63,
+  0xe9, 0x9e, 0xef, 0x24, 0xa5, 0xaf, 0xd7, 0x4c, 0x8d, 0xc3, 0x9e, 0xe5,
+  0x13, 0xb1, 0xc2, 0xa3, 0x98, 0xaf, 0x54, 0xd0, 0x83, 0x67, 0x32, 0xe1,
+  0xef, 0x09, 0xe4, 0x67, 0x30, 0xd9, 0x7c, 0x9b, 0x23, 0xc4, 0x14, 0x0f,
+  0x2f, 0xf5, 0xfe, 0x82, 0xac, 0x1e, 0xfc, 0x98, 0x87, 0x6a, 0x70, 0x6b,
+  0x15, 0x37, 0xd5, 0xcf, 0xf5, 0x17, 0x57, 0x9a, 0x51, 0x87, 0xdb, 0x62,
+  0x5c, 0x1d, 0xee, 0x24, 0xdd, 0xe2, 0x48, 0x73, 0x5c, 0xad, 0x73, 0xa9,
+  0xd5, 0xf6, 0xfb, 0xaa, 0xba, 0x63, 0x63, 0x96, 0x02, 0xcc, 0xd7, 0x05,
+  0xbd, 0x37, 0x5e, 0x92, 0xe2, 0x0e, 0xb5, 0x2a, 0xad, 0x94, 0xaa, 0x1c,
+  0xea, 0xe3, 0x3c, 0x15, 0x8c, 0x62, 0x09, 0xfa, 0xc8, 0x82, 0x91, 0xd9,
+  0x17, 0x27, 0xe5, 0x05, 0xcd, 0x24, 0xf8, 0x90, 0xef, 0x3c, 0xcf, 0x7e,
+  0x64, 0x22, 0xd9, 0xef, 0x63, 0xce, 0xca, 0x7c, 0x1d, 0xa8, 0x57, 0xaf,
+  0x47, 0xc8, 0x8f, 0x4a, 0xd8, 0x8b, 0xad, 0x03, 0x38, 0xaa, 0x67, 0xdf,
+  0x15, 0x6c, 0x07, 0x5f, 0x7e, 0x11, 0x9c, 0x3e, 0x1c, 0xc1, 0xd3, 0x4f,
+  0xae, 0x56, 0x8c, 0xc6, 0xa3, 0xaa, 0x8c, 0x35, 0x92, 0x65, 0xff, 0x8d,
+  0x03, 0xba, 0xe0, 0x19, 0xe3, 0x8f, 0xc4, 0xe2, 0xa6, 0x0a, 0x9b, 0x46,
+  0x4a, 0x3a, 0x05, 0xb7, 0xea, 0x38, 0x71, 0xf5, 0x53, 0x1f, 0x2a, 0xee,
+  0xf5, 0xca, 0xd1, 0xcb, 0x5b, 0xd6, 0xf8, 0x24, 0x59, 0xab, 0xc9, 0xe7,
+  0x58, 0xe4, 0x06, 0xcb, 0x2d, 0xf8, 0x7f, 0xbb, 0x82, 0x83, 0x8f, 0xcc,
+  0xfb, 0xe5, 0xfa, 0x76, 0x8a, 0x6e, 0x9f, 0xa1, 0xcf, 0x58, 0xbd, 0xee,
+  0x59, 0xba, 0x53, 0xeb, 0x6e, 0x88, 0xf2, 0xae, 0xaa, 0x7e, 0x59, 0xdc,
+  0xf3, 0xe8, 0xea, 0xb3, 0xbe, 0xf4, 0xf1, 0x29, 0xe9, 0x88, 0xc6, 0xdf,
+  0x9e, 0x8d, 0xce, 0xde, 0x6a, 0x4d, 0xba, 0xd2, 0xab, 0x0f, 0xd6, 0x36,
+  0x8a, 0xe5, 0x05, 0xfa, 0x86, 0x34, 0x5f, 0xc3, 0x52, 0x45, 0x33, 0xf2,
+  0x82, 0x65, 0x40, 0x0d, 0x8b, 0x2c, 0x55, 0xb9, 0x51, 0x5d, 0x18, 0x35,
+  0x16, 0xbb, 0xfa, 0x34, 0x8c, 0x26, 0x75, 0x2d, 0xb8, 0x64, 0x25, 0xad,
+  0xbb, 0x3c, 0x3b, 0xa1, 0xb8, 0x94, 0xca, 0xbb, 0x10, 0x9a, 0x2b, 0x9a,
+  0x27, 0x17, 0x6a, 0x6d, 0x05, 0x0d, 0x6b, 0xca, 0x2f, 0xf4, 0x62, 0xab,
+  0x76, 0x84, 0xda, 0xc4, 0xa8, 0x1f, 0xf1, 0x78, 0x90, 0x18, 0x67, 0xe4,
+  0x07, 0x26, 0xae, 0x69, 0x28, 0x71, 0x83, 0x1f, 0xd7, 0xd2, 0x7a, 0x95,
+  0xd7, 0xab, 0xe4, 0x51, 0xc9, 0xd7, 0xff, 0x02, 0x0e, 0x0b, 0xbd, 0x94,
+  0x3d, 0x47, 0x00, 0x00,
 }
 
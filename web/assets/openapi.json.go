@@ -0,0 +1,146 @@
+package assets
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+// openapi_json returns the raw, uncompressed file data data.
+func Openapi_json() []byte {
+	gz, err := gzip.NewReader(bytes.NewBuffer([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xed, 0x19,
+		0xd9, 0x6e, 0xe3, 0x36, 0xf0, 0x3d, 0x5f, 0x41, 0xa8, 0x05, 0xda, 0x02,
+		0x3e, 0x92, 0x26, 0x4f, 0xdd, 0xa7, 0x60, 0xb3, 0xbb, 0x48, 0x9b, 0x74,
+		0x17, 0x49, 0xb6, 0x07, 0x8a, 0xa0, 0xa0, 0xa5, 0x91, 0xcd, 0x44, 0x22,
+		0x15, 0x92, 0xca, 0x51, 0xc3, 0xff, 0xde, 0x19, 0x52, 0xa7, 0x23, 0xdb,
+		0x5a, 0xc7, 0xbb, 0xdb, 0x87, 0xe6, 0x21, 0x11, 0xc9, 0xb9, 0x38, 0x37,
+		0x27, 0xf3, 0x3d, 0xc6, 0x02, 0x95, 0x81, 0xe4, 0x99, 0x08, 0x7e, 0x62,
+		0xc1, 0xe1, 0x68, 0x7f, 0x74, 0x18, 0x0c, 0x68, 0x57, 0xc8, 0x58, 0xe1,
+		0xd6, 0x1c, 0xbf, 0x71, 0x65, 0x85, 0x4d, 0x80, 0x20, 0x7e, 0x53, 0x22,
+		0x84, 0x94, 0x8b, 0x84, 0x1d, 0x7f, 0x38, 0x75, 0x90, 0x78, 0x7a, 0x0f,
+		0xda, 0x08, 0x25, 0xe9, 0xfc, 0x00, 0x29, 0xec, 0x97, 0xfb, 0x11, 0x98,
+		0x50, 0x8b, 0xcc, 0x16, 0x67, 0x3f, 0x5f, 0xbe, 0xff, 0x95, 0xd0, 0x58,
+		0xac, 0x34, 0xb3, 0x33, 0x60, 0xf7, 0x25, 0x31, 0xc3, 0x8c, 0x55, 0x1a,
+		0x22, 0x36, 0x79, 0x6a, 0x1f, 0x30, 0x03, 0x1a, 0xa9, 0x8f, 0xd8, 0x05,
+		0xdc, 0xe5, 0x60, 0xac, 0x61, 0x5c, 0x03, 0xe3, 0x39, 0x02, 0x49, 0x2b,
+		0x42, 0x6e, 0x11, 0xe7, 0x41, 0xd8, 0x19, 0xe3, 0xd2, 0x51, 0xb6, 0xea,
+		0x16, 0x24, 0x13, 0xd2, 0x51, 0x39, 0x46, 0x30, 0xa5, 0xc5, 0x3f, 0x9c,
+		0x04, 0x60, 0x33, 0xe0, 0x11, 0xe8, 0x01, 0x43, 0xde, 0x0e, 0x83, 0x20,
+		0x0c, 0x18, 0x12, 0x9c, 0x85, 0x4a, 0xdd, 0x0a, 0x60, 0x2a, 0x76, 0xbb,
+		0x0f, 0x30, 0x41, 0x12, 0x16, 0x74, 0xcc, 0x43, 0x18, 0xb1, 0x2b, 0xa2,
+		0x69, 0x98, 0x04, 0xe4, 0x45, 0xc7, 0x1a, 0x09, 0x31, 0x13, 0xa2, 0xda,
+		0xdc, 0x45, 0xde, 0xbd, 0xb9, 0xc2, 0xad, 0x52, 0x3a, 0xe9, 0x61, 0x1e,
+		0xb4, 0xb0, 0xd0, 0x00, 0xe2, 0x49, 0xc2, 0x14, 0x1e, 0x68, 0x33, 0x62,
+		0xbf, 0x3b, 0x79, 0x97, 0x78, 0x0f, 0x6a, 0x1a, 0x0e, 0x0e, 0xa9, 0xe0,
+		0x95, 0x88, 0x76, 0xc5, 0xb7, 0x40, 0xf8, 0xce, 0xb0, 0xd7, 0x97, 0x17,
+		0x6f, 0xdb, 0x57, 0xfd, 0x63, 0x48, 0x7b, 0x43, 0x27, 0x6a, 0x71, 0xd3,
+		0x51, 0x80, 0x46, 0x58, 0x38, 0x5b, 0x7a, 0x25, 0x1a, 0xb4, 0xc1, 0x5f,
+		0xf3, 0x20, 0xd7, 0x09, 0x19, 0x63, 0x8c, 0x26, 0x1f, 0xdf, 0x1f, 0x04,
+		0x8b, 0xeb, 0x02, 0x24, 0xcc, 0x51, 0xe6, 0x27, 0x0f, 0xe3, 0x68, 0xd3,
+		0xe7, 0xf5, 0x62, 0xc0, 0xe6, 0x41, 0xc1, 0xd9, 0x6f, 0x78, 0xf8, 0x8c,
+		0xdb, 0x99, 0xa9, 0xfd, 0x63, 0x5c, 0x9b, 0xb2, 0xda, 0xc4, 0xed, 0x29,
+		0xd8, 0xc6, 0x92, 0xd8, 0xe4, 0x69, 0xca, 0x35, 0x71, 0x09, 0xce, 0x84,
+		0xb1, 0x0d, 0x0f, 0x28, 0x7c, 0xa6, 0xcb, 0x6f, 0x2e, 0xc0, 0xe6, 0x1a,
+		0x2d, 0xc0, 0x59, 0xc6, 0xa7, 0xce, 0x4a, 0x35, 0xda, 0x00, 0xf5, 0xf3,
+		0x80, 0x6a, 0x63, 0xb1, 0xd0, 0xc6, 0x8e, 0xd8, 0x47, 0x03, 0x4e, 0x21,
+		0x2a, 0x41, 0x15, 0x38, 0x73, 0xd0, 0xb9, 0x66, 0x78, 0x3b, 0xa3, 0xb4,
+		0x29, 0x4d, 0xac, 0xc1, 0x64, 0x4a, 0x12, 0xac, 0x62, 0x31, 0xd8, 0xd0,
+		0xbb, 0x03, 0x8f, 0x6e, 0xd0, 0xe2, 0xd2, 0x3a, 0x3e, 0x66, 0xd4, 0x14,
+		0x29, 0xe3, 0x9a, 0xa7, 0x60, 0x0b, 0x2d, 0x56, 0xfb, 0x0c, 0xb5, 0x23,
+		0xf1, 0x80, 0xc4, 0x8c, 0x1d, 0xcf, 0x60, 0x40, 0xb1, 0x43, 0x6b, 0x34,
+		0x27, 0xde, 0x14, 0x97, 0x26, 0x9c, 0xa1, 0xb0, 0xa4, 0x88, 0xc0, 0x3e,
+		0x65, 0x0e, 0xd6, 0x58, 0x2d, 0xe4, 0x94, 0x0e, 0x41, 0xe6, 0x29, 0x91,
+		0x44, 0xa4, 0x89, 0x7a, 0xa4, 0x1d, 0xae, 0xc3, 0x99, 0xb8, 0x07, 0xfa,
+		0xb4, 0x9a, 0x9b, 0x59, 0x70, 0x3d, 0x20, 0x95, 0xc4, 0x3c, 0x4f, 0x48,
+		0x9b, 0x05, 0xe0, 0x62, 0x31, 0xe8, 0x94, 0x82, 0xb4, 0x52, 0x10, 0xea,
+		0x2d, 0xc6, 0x2a, 0x5a, 0x77, 0x1d, 0x54, 0x96, 0x6c, 0xf3, 0x36, 0x4f,
+		0x92, 0xa1, 0x85, 0x47, 0x8b, 0xee, 0x49, 0x82, 0x93, 0x43, 0x86, 0xe8,
+		0xed, 0x14, 0x69, 0xee, 0x2f, 0x1a, 0x20, 0x4f, 0x27, 0xb4, 0x94, 0xca,
+		0x02, 0xda, 0x0b, 0xef, 0x24, 0x3d, 0x09, 0x1f, 0x2d, 0x7c, 0x4a, 0x9a,
+		0xde, 0x46, 0x3c, 0xcf, 0x67, 0x27, 0x37, 0xf5, 0xa2, 0xee, 0x84, 0x54,
+		0xac, 0x55, 0xda, 0x43, 0x6f, 0xe4, 0xae, 0x2c, 0xe2, 0x4f, 0x03, 0xf6,
+		0x27, 0xfe, 0x0c, 0xcf, 0xcf, 0x87, 0x27, 0x27, 0xa3, 0x8d, 0xde, 0x82,
+		0xb9, 0x24, 0xe5, 0xce, 0x0b, 0x22, 0xcc, 0x7d, 0x2b, 0x65, 0xc8, 0x31,
+		0x39, 0x26, 0x9b, 0x85, 0x38, 0xe3, 0x5e, 0x06, 0xf6, 0xbd, 0x90, 0x61,
+		0x92, 0x1b, 0xf4, 0xba, 0x1f, 0x3e, 0x8b, 0x3c, 0xa9, 0x90, 0xc3, 0x28,
+		0xd7, 0x2e, 0x11, 0x6f, 0x16, 0xeb, 0x12, 0x42, 0x25, 0xa3, 0x95, 0x5e,
+		0x41, 0xa9, 0x79, 0x8a, 0x76, 0x5f, 0x1d, 0x01, 0x8f, 0x5f, 0x8e, 0x19,
+		0x15, 0x83, 0xad, 0x43, 0x9e, 0x16, 0x25, 0x81, 0x5c, 0xba, 0xaf, 0xeb,
+		0x55, 0x8c, 0x28, 0x4e, 0x36, 0xdf, 0xe6, 0xb5, 0xc2, 0xfc, 0x3a, 0x34,
+		0x40, 0xe9, 0x8a, 0x6a, 0x23, 0x61, 0x51, 0x29, 0xb1, 0x2c, 0xcd, 0xd1,
+		0xd6, 0x54, 0x86, 0x26, 0xc0, 0x32, 0x4c, 0x7f, 0x98, 0xe6, 0xb6, 0x0c,
+		0xbb, 0x44, 0xa4, 0xc2, 0xf6, 0xbb, 0x73, 0xa9, 0xbd, 0x81, 0x73, 0x01,
+		0x91, 0xba, 0x7b, 0x1f, 0xac, 0x22, 0x3c, 0x01, 0x74, 0x27, 0xe8, 0x71,
+		0x49, 0x97, 0xcb, 0x5f, 0x61, 0x12, 0xa7, 0xba, 0xd0, 0xec, 0x22, 0x7c,
+		0xd6, 0x77, 0xa5, 0xd3, 0xce, 0xc4, 0xb6, 0x79, 0x85, 0xc7, 0xb6, 0x33,
+		0xad, 0xf4, 0x16, 0xc3, 0x97, 0x9c, 0xfe, 0x62, 0x54, 0x52, 0x5c, 0x37,
+		0x2a, 0x4e, 0x59, 0xa2, 0x4c, 0xab, 0x82, 0xe2, 0xc1, 0x8f, 0xfb, 0xfb,
+		0x8e, 0xcc, 0x92, 0x38, 0xc7, 0x1d, 0xd5, 0xd1, 0x71, 0x46, 0xff, 0xb6,
+		0x68, 0x6d, 0x87, 0xc3, 0xb3, 0x2c, 0xa1, 0xb6, 0x09, 0x71, 0xc6, 0x37,
+		0xc6, 0x21, 0xce, 0x9b, 0xa2, 0x7d, 0xab, 0x21, 0x26, 0x62, 0xdf, 0x8c,
+		0x43, 0x95, 0x22, 0x7b, 0xc4, 0x33, 0x63, 0x7f, 0x6e, 0xc6, 0x1f, 0x90,
+		0x3e, 0x4a, 0xdb, 0x56, 0x5b, 0x70, 0x54, 0x88, 0xd3, 0x89, 0x5a, 0x5d,
+		0x62, 0xfc, 0x46, 0x6b, 0x85, 0x61, 0xb4, 0x84, 0x7a, 0xf0, 0x09, 0xa8,
+		0x15, 0x66, 0xf9, 0xe5, 0xff, 0x16, 0x24, 0x9b, 0x2d, 0xc8, 0x78, 0x2e,
+		0xa2, 0x45, 0xb3, 0x0f, 0x69, 0x57, 0xef, 0x15, 0x0c, 0x6b, 0xa0, 0xf1,
+		0x69, 0x54, 0xf4, 0x45, 0x1b, 0xbb, 0x98, 0x77, 0x80, 0x51, 0x55, 0x6b,
+		0x3c, 0x78, 0x81, 0x01, 0xaf, 0x9a, 0x8d, 0xef, 0x6e, 0x2d, 0x57, 0x75,
+		0xed, 0x5d, 0xe6, 0x3b, 0x7a, 0x91, 0x0d, 0x2a, 0x2d, 0x45, 0x90, 0xa0,
+		0xf2, 0x56, 0x2a, 0xea, 0xc4, 0x1d, 0xaf, 0xd2, 0xd5, 0x92, 0x2a, 0xce,
+		0xd5, 0x3d, 0x98, 0xa5, 0x97, 0x00, 0x76, 0x6a, 0xb4, 0xe1, 0x5a, 0x22,
+		0xd7, 0xc5, 0x7b, 0x86, 0x86, 0x09, 0xec, 0xd7, 0x00, 0xcb, 0x10, 0x89,
+		0x9c, 0xe0, 0xfb, 0x41, 0xa1, 0xbd, 0xa8, 0x83, 0x76, 0x5d, 0xbe, 0xc0,
+		0x86, 0x5a, 0x63, 0x8a, 0xd7, 0x11, 0x86, 0x1a, 0x13, 0x31, 0xcb, 0x72,
+		0x8d, 0x51, 0x22, 0x90, 0xba, 0xce, 0x61, 0x4d, 0x8b, 0x57, 0xa7, 0x03,
+		0x87, 0xd1, 0x2f, 0xdf, 0x4d, 0x94, 0x4a, 0x80, 0xcb, 0xa0, 0xd5, 0xb0,
+		0xc5, 0x3c, 0x31, 0xb0, 0x58, 0xf4, 0x8c, 0xed, 0xa3, 0x2e, 0xd7, 0xf0,
+		0xca, 0x8b, 0x46, 0xcf, 0xe2, 0xe7, 0x70, 0xfb, 0xd0, 0x3b, 0xfa, 0x7c,
+		0xa1, 0x37, 0x76, 0xa5, 0x6c, 0x67, 0xf1, 0x97, 0x29, 0xb3, 0x3a, 0x00,
+		0xcf, 0xb9, 0xbe, 0x6d, 0x7a, 0x15, 0xe3, 0x86, 0xf9, 0x9a, 0xfa, 0xb2,
+		0x48, 0xcc, 0xb3, 0xc8, 0x55, 0xd0, 0xaf, 0x11, 0x91, 0xff, 0x51, 0xab,
+		0x16, 0x2d, 0xca, 0xd7, 0xb3, 0x6b, 0x21, 0xc0, 0xff, 0x96, 0xdd, 0xb5,
+		0x65, 0x79, 0x1e, 0x09, 0xf5, 0x65, 0x0a, 0xe6, 0x89, 0x7a, 0x90, 0x89,
+		0xe2, 0xe5, 0x28, 0xa5, 0xc8, 0xcc, 0x2f, 0xb5, 0x69, 0x45, 0xe8, 0xb9,
+		0x2d, 0xe9, 0x6a, 0xe3, 0x34, 0x83, 0xe9, 0xb2, 0x15, 0xd7, 0xbd, 0x66,
+		0x26, 0x42, 0x92, 0xbc, 0x3b, 0xaf, 0x96, 0x7b, 0xe5, 0x6f, 0x3f, 0x98,
+		0xa9, 0x91, 0xeb, 0x51, 0x4a, 0x39, 0x89, 0xb9, 0x24, 0x49, 0x5b, 0x4a,
+		0xa8, 0xc6, 0x32, 0xb5, 0xec, 0x33, 0x6b, 0xb3, 0xaa, 0x16, 0x15, 0x4d,
+		0x34, 0xd7, 0xd0, 0xf0, 0x9e, 0xc6, 0xf0, 0xa6, 0x46, 0xe3, 0x99, 0xf8,
+		0x05, 0x9e, 0xea, 0x9a, 0xe6, 0x67, 0x50, 0xb4, 0x2e, 0x8b, 0x5e, 0xe5,
+		0x21, 0x7f, 0x97, 0xf8, 0x6d, 0x17, 0x6a, 0x39, 0x48, 0x25, 0xe0, 0xa9,
+		0xcb, 0x0e, 0x15, 0x11, 0xd1, 0x78, 0x1a, 0xd1, 0xc0, 0xc8, 0xbf, 0x77,
+		0xee, 0x72, 0xa1, 0x81, 0x00, 0xa9, 0x02, 0x6f, 0x7a, 0x75, 0xb5, 0x98,
+		0x76, 0x79, 0x46, 0xe0, 0x75, 0xdd, 0xd5, 0x10, 0x4b, 0x06, 0x74, 0xb6,
+		0xdb, 0xf0, 0x2e, 0x4c, 0xbb, 0x58, 0x12, 0xad, 0x38, 0x6e, 0x0a, 0x56,
+		0x27, 0x82, 0x56, 0x2c, 0x94, 0x77, 0x54, 0x93, 0x1b, 0x08, 0x6d, 0xab,
+		0x01, 0xd1, 0x0a, 0x1b, 0x19, 0x2b, 0x9e, 0x7b, 0xbe, 0x88, 0xba, 0xd5,
+		0xd3, 0xf2, 0xcd, 0x62, 0x1c, 0xd2, 0xf5, 0xc2, 0x78, 0x0e, 0x17, 0x6d,
+		0x86, 0x2b, 0x27, 0x49, 0x1b, 0x01, 0xdd, 0xd3, 0xbf, 0xcf, 0x7c, 0x60,
+		0x68, 0x05, 0x3a, 0xc6, 0x12, 0x72, 0xf9, 0x52, 0x6f, 0x12, 0xf0, 0x23,
+		0xa3, 0x75, 0xaf, 0xf5, 0x36, 0x91, 0xb2, 0x2e, 0x3d, 0x6b, 0xc4, 0xda,
+		0x60, 0xc6, 0x72, 0xad, 0xa1, 0x0f, 0x64, 0x31, 0x83, 0xeb, 0x03, 0xea,
+		0xdb, 0xd0, 0x3e, 0x90, 0x6e, 0x00, 0xb6, 0x59, 0x9d, 0xf5, 0x80, 0xac,
+		0x07, 0x2c, 0x4d, 0x05, 0x5a, 0x91, 0xad, 0x35, 0xf7, 0x81, 0x6d, 0x21,
+		0x35, 0x9b, 0x5f, 0xbd, 0x41, 0x95, 0xfa, 0x3b, 0xac, 0xb7, 0xa4, 0xfc,
+		0x8f, 0x17, 0x67, 0xf5, 0xfc, 0xb4, 0xca, 0xb7, 0xeb, 0xde, 0x04, 0xee,
+		0xc5, 0xf8, 0x62, 0xef, 0x6f, 0xcf, 0x96, 0xd7, 0x5d, 0xb5, 0x77, 0x61,
+		0x6e, 0xa9, 0xc0, 0xcf, 0x6d, 0x7b, 0xa9, 0xc0, 0xbf, 0xf9, 0xab, 0x7f,
+		0x60, 0x48, 0x1a, 0x7c, 0xfa, 0xa1, 0x03, 0x3d, 0xbe, 0x5f, 0x31, 0x3e,
+		0xa1, 0xc1, 0x0a, 0x53, 0x7e, 0x2a, 0x9f, 0xd0, 0x7c, 0x8d, 0x0e, 0x96,
+		0xfd, 0xd5, 0x0d, 0x08, 0xb6, 0xe7, 0xe8, 0xe7, 0x0b, 0x5d, 0x1c, 0xdd,
+		0x14, 0xbc, 0x64, 0xb9, 0xc6, 0x30, 0x55, 0xd2, 0xfc, 0x74, 0xcb, 0x04,
+		0x50, 0x25, 0xdc, 0x55, 0x83, 0x8c, 0x46, 0x99, 0xdb, 0x5b, 0xec, 0xfd,
+		0x0b, 0xd3, 0xf1, 0x56, 0x33, 0x65, 0x1a, 0x00, 0x00,
+	}))
+
+	if err != nil {
+		panic("Decompression failed: " + err.Error())
+	}
+
+	var b bytes.Buffer
+	io.Copy(&b, gz)
+	gz.Close()
+
+	return b.Bytes()
+}
@@ -0,0 +1,218 @@
+package web
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"../model"
+)
+
+const (
+	sessionCookie   = "voicemail_session"
+	sessionLifetime = 30 * 24 * time.Hour
+)
+
+type contextKey int
+
+const sessionKey contextKey = 0
+
+// publicPaths can be requested without logging in.
+var publicPaths = map[string]bool{
+	"/login":                              true,
+	"/css":                                true,
+	"/js/zepto.min.js":                    true,
+	"/img/glyphicons-halflings.png":       true,
+	"/img/glyphicons-halflings-white.png": true,
+	"/img/apple-touch-icon.png":           true,
+	"/healthz":                            true,
+	"/readyz":                             true,
+	apiPrefix + "openapi.json":            true,
+}
+
+// currentSession returns the session of a request that passed
+// requireLogin.
+func currentSession(r *http.Request) model.Session {
+	session, _ := r.Context().Value(sessionKey).(model.Session)
+	return session
+}
+
+// permissions returns what the user of a request may access.
+func permissions(r *http.Request, mailboxes model.Mailboxes) model.Permissions {
+	return mailboxes.Permissions(currentSession(r).User.Mailboxes)
+}
+
+func validCSRFToken(session model.Session, r *http.Request) bool {
+	token := r.Header.Get("X-CSRF-Token")
+	if token == "" {
+		token = r.FormValue("csrf")
+	}
+	return token != "" &&
+		subtle.ConstantTimeCompare([]byte(token), []byte(session.CSRFToken)) == 1
+}
+
+// bearerToken returns the API token in the Authorization header.
+func bearerToken(r *http.Request) string {
+	const prefix = "Bearer "
+	header := r.Header.Get("Authorization")
+	if len(header) > len(prefix) && strings.EqualFold(header[:len(prefix)], prefix) {
+		return strings.TrimSpace(header[len(prefix):])
+	}
+	return ""
+}
+
+// requiredScope returns the scope an API token needs for a request.
+func requiredScope(r *http.Request) model.Scope {
+	if r.URL.Path == "/tokens" || strings.HasPrefix(r.URL.Path, "/tokens/") {
+		return model.ScopeAdmin
+	}
+	if r.Method == "GET" || r.Method == "HEAD" {
+		return model.ScopeRead
+	}
+	return model.ScopeWrite
+}
+
+// requireLogin only passes requests with a valid session cookie or API
+// token to next.  Form submissions with a session cookie must also
+// carry the session's CSRF token.
+func requireLogin(db model.Database, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if publicPaths[r.URL.Path] || strings.HasPrefix(r.URL.Path, feedPrefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var session model.Session
+		var err error
+		bearer := bearerToken(r)
+		if bearer != "" {
+			if session, err = db.AuthenticateToken(bearer); err == model.ErrNoToken {
+				err = model.ErrNoSession
+			}
+		} else {
+			var cookie *http.Cookie
+			if cookie, err = r.Cookie(sessionCookie); err == nil {
+				session, err = db.GetSession(cookie.Value)
+			}
+		}
+		if err != nil && err != http.ErrNoCookie && err != model.ErrNoSession {
+			http.Error(w, fmt.Sprintf("%v", err), http.StatusInternalServerError)
+			return
+		} else if err != nil {
+			if strings.HasPrefix(r.URL.Path, apiPrefix) {
+				writeJSONError(w, http.StatusUnauthorized, "login required")
+			} else if r.Method == "GET" && r.URL.Path == "/" {
+				http.Redirect(w, r, "/login?next="+url.QueryEscape(r.URL.RequestURI()),
+					http.StatusSeeOther)
+			} else {
+				http.Error(w, "Login required", http.StatusUnauthorized)
+			}
+			return
+		}
+
+		// Bearer tokens are not sent automatically by browsers and
+		// need no CSRF protection.
+		if bearer == "" && r.Method != "GET" && r.Method != "HEAD" && !validCSRFToken(session, r) {
+			if strings.HasPrefix(r.URL.Path, apiPrefix) {
+				writeJSONError(w, http.StatusForbidden, "invalid CSRF token")
+			} else {
+				http.Error(w, "Invalid CSRF token", http.StatusForbidden)
+			}
+			return
+		}
+
+		if scope := requiredScope(r); !session.HasScope(scope) {
+			message := fmt.Sprintf("API token lacks the %s scope", scope)
+			if strings.HasPrefix(r.URL.Path, apiPrefix) {
+				writeJSONError(w, http.StatusForbidden, message)
+			} else {
+				http.Error(w, message, http.StatusForbidden)
+			}
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), sessionKey, session)))
+	})
+}
+
+// localURL returns target if it is a path on this server and "/"
+// otherwise.
+func localURL(target string) string {
+	if !strings.HasPrefix(target, "/") || strings.HasPrefix(target, "//") ||
+		strings.HasPrefix(target, "/\\") {
+		return "/"
+	}
+	return target
+}
+
+type Login struct {
+	Next  string
+	User  string
+	Error string
+}
+
+func loginHandler(db model.Database) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		login := Login{Next: localURL(r.FormValue("next"))}
+
+		if r.Method == "POST" {
+			login.User = r.FormValue("user")
+			user, err := db.Authenticate(login.User, r.FormValue("password"))
+			if err == nil {
+				var session model.Session
+				if session, err = db.CreateSession(user, sessionLifetime); err == nil {
+					http.SetCookie(w, &http.Cookie{
+						Name:     sessionCookie,
+						Value:    session.Token,
+						Path:     "/",
+						Expires:  session.Expires,
+						HttpOnly: true,
+						Secure:   r.TLS != nil,
+						SameSite: http.SameSiteLaxMode,
+					})
+					logger.Info("User logged in", "user", user.Name, "remote", r.RemoteAddr)
+					http.Redirect(w, r, login.Next, http.StatusSeeOther)
+					return
+				}
+			}
+
+			if err != model.ErrInvalidCredentials {
+				http.Error(w, fmt.Sprintf("%v", err), http.StatusInternalServerError)
+				return
+			}
+			logger.Warn("Failed login", "user", login.User, "remote", r.RemoteAddr)
+			login.Error = "Benutzername oder Passwort falsch."
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+
+		if err := rootTemplate.ExecuteTemplate(w, "login", login); err != nil {
+			http.Error(w, fmt.Sprintf("%v", err), http.StatusInternalServerError)
+		}
+	}
+}
+
+func logoutHandler(db model.Database) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if err := db.DeleteSession(currentSession(r).Token); err != nil {
+			http.Error(w, fmt.Sprintf("%v", err), http.StatusInternalServerError)
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:   sessionCookie,
+			Value:  "",
+			Path:   "/",
+			MaxAge: -1,
+		})
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+	}
+}
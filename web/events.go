@@ -0,0 +1,68 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"../model"
+)
+
+// eventKeepAlive is the interval of comments sent to keep idle event
+// streams open through proxies.
+const eventKeepAlive = 30 * time.Second
+
+// eventsHandler streams the changes of the voicemails the user may
+// access as Server-Sent Events.  The event name is the model.EventType,
+// the data the voicemail in the format of the API.  Failures to receive
+// a voicemail are not sent; they have no stored voicemail to show.
+func eventsHandler(db model.Database, mailboxes model.Mailboxes) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+			return
+		}
+
+		events, unsubscribe := db.Subscribe()
+		defer unsubscribe()
+
+		p := permissions(r, mailboxes)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, "retry: 5000\n\n")
+		flusher.Flush()
+
+		keepAlive := time.NewTicker(eventKeepAlive)
+		defer keepAlive.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+
+			case <-keepAlive.C:
+				fmt.Fprint(w, ": keep-alive\n\n")
+
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if event.Type == model.VoicemailFailed || !p.Allows(event.Voicemail.Called) {
+					continue
+				}
+				data, err := json.Marshal(newAPIVoicemail(event.Voicemail, mailboxes))
+				if err != nil {
+					logger.Error("Unable to encode event", "voicemail", event.Voicemail.Id, "error", err)
+					continue
+				}
+				fmt.Fprintf(w, "event: %s\nid: %d\ndata: %s\n\n", event.Type, event.Voicemail.Id, data)
+			}
+			flusher.Flush()
+		}
+	}
+}
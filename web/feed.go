@@ -0,0 +1,303 @@
+package web
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"../model"
+)
+
+// feedPrefix starts the URLs of the feeds.  Podcast apps cannot send
+// headers, so an API token with the feed scope is part of the URL:
+//
+//	/feed/TOKEN/rss.xml           RSS 2.0 feed of all voicemails
+//	/feed/TOKEN/atom.xml          Atom feed of all voicemails
+//	/feed/TOKEN/MAILBOX/rss.xml   feeds of one mailbox
+//	/feed/TOKEN/audio/ID.mp3      enclosure of a voicemail
+const feedPrefix = "/feed/"
+
+// feedLimit is the number of voicemails in a feed.
+const feedLimit = 100
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Itunes  string     `xml:"xmlns:itunes,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Language    string    `xml:"language"`
+	PubDate     string    `xml:"pubDate,omitempty"`
+	Items       []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string       `xml:"title"`
+	Description string       `xml:"description"`
+	PubDate     string       `xml:"pubDate"`
+	GUID        rssGUID      `xml:"guid"`
+	Enclosure   rssEnclosure `xml:"enclosure"`
+	Duration    string       `xml:"itunes:duration"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Id      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel    string `xml:"rel,attr,omitempty"`
+	Href   string `xml:"href,attr"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length int64  `xml:"length,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Id      string     `xml:"id"`
+	Title   string     `xml:"title"`
+	Updated string     `xml:"updated"`
+	Summary string     `xml:"summary"`
+	Link    []atomLink `xml:"link"`
+}
+
+// feedItem holds what both feed formats show of a voicemail.
+type feedItem struct {
+	voicemail   model.Voicemail
+	title       string
+	description string
+	guid        string
+	link        string
+	audioURL    string
+	size        int64
+}
+
+// baseURL returns the public URL of the server without a trailing
+// slash.
+func baseURL(r *http.Request, publicURL string) string {
+	if publicURL != "" {
+		return strings.TrimRight(publicURL, "/")
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// formatDuration formats a duration as HH:MM:SS.
+func formatDuration(d time.Duration) string {
+	s := int(d.Seconds() + 0.5)
+	return fmt.Sprintf("%02d:%02d:%02d", s/3600, s/60%60, s%60)
+}
+
+func feedItems(voicemails []model.Voicemail, mailboxes model.Mailboxes, voicemailDir string, base string, token string) []feedItem {
+	items := []feedItem{}
+	for _, voicemail := range voicemails {
+		item := feedItem{
+			voicemail: voicemail,
+			title:     "Anruf von " + voicemail.Caller,
+			description: fmt.Sprintf("Nachricht von %s für %s am %s, Dauer %s.",
+				voicemail.Caller, voicemail.Called, voicemail.Date.Format("02.01.2006 um 15:04"),
+				voicemail.Duration),
+			guid:     "urn:voicemail:" + path.Base(voicemail.VoicemailPath),
+			link:     base + apiPrefix + "voicemails/" + strconv.Itoa(voicemail.Id),
+			audioURL: base + feedPrefix + token + "/audio/" + strconv.Itoa(voicemail.Id) + ".mp3",
+		}
+		if mailbox, ok := mailboxes.ForNumber(voicemail.Called); ok && mailbox.Title != "" {
+			item.title += " (" + mailbox.Title + ")"
+		}
+		if voicemail.Notes != "" {
+			item.description += "\n\n" + voicemail.Notes
+		}
+		if info, err := os.Stat(path.Join(voicemailDir, path.Base(voicemail.VoicemailPath))); err == nil {
+			item.size = info.Size()
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func writeRSS(w http.ResponseWriter, title string, link string, items []feedItem) error {
+	feed := rss{
+		Version: "2.0",
+		Itunes:  "http://www.itunes.com/dtds/podcast-1.0.dtd",
+		Channel: rssChannel{
+			Title:       title,
+			Link:        link,
+			Description: "Nachrichten auf dem Anrufbeantworter",
+			Language:    "de",
+			Items:       []rssItem{},
+		},
+	}
+	if len(items) > 0 {
+		feed.Channel.PubDate = items[0].voicemail.Date.Format(time.RFC1123Z)
+	}
+	for _, item := range items {
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       item.title,
+			Description: item.description,
+			PubDate:     item.voicemail.Date.Format(time.RFC1123Z),
+			GUID:        rssGUID{false, item.guid},
+			Enclosure:   rssEnclosure{item.audioURL, item.size, "audio/mpeg"},
+			Duration:    formatDuration(item.voicemail.Duration),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	w.Write([]byte(xml.Header))
+	return xml.NewEncoder(w).Encode(feed)
+}
+
+func writeAtom(w http.ResponseWriter, id string, title string, link string, self string, items []feedItem) error {
+	feed := atomFeed{
+		Id:      id,
+		Title:   title,
+		Updated: time.Unix(0, 0).UTC().Format(time.RFC3339),
+		Link: []atomLink{
+			{Rel: "alternate", Href: link},
+			{Rel: "self", Href: self},
+		},
+		Author:  atomAuthor{"Anrufbeantworter"},
+		Entries: []atomEntry{},
+	}
+	if len(items) > 0 {
+		feed.Updated = items[0].voicemail.Date.Format(time.RFC3339)
+	}
+	for _, item := range items {
+		feed.Entries = append(feed.Entries, atomEntry{
+			Id:      item.guid,
+			Title:   item.title,
+			Updated: item.voicemail.Date.Format(time.RFC3339),
+			Summary: item.description,
+			Link: []atomLink{
+				{Rel: "alternate", Href: item.link, Type: "application/json"},
+				{Rel: "enclosure", Href: item.audioURL, Type: "audio/mpeg", Length: item.size},
+			},
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	w.Write([]byte(xml.Header))
+	return xml.NewEncoder(w).Encode(feed)
+}
+
+// feedHandler serves the feeds and their enclosures.  It authenticates
+// the API token in the URL itself, feedPrefix is public.
+func feedHandler(db model.Database, voicemailDir string, mailboxes model.Mailboxes, publicURL string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" && r.Method != "HEAD" {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, feedPrefix), "/")
+		if len(parts) < 2 || len(parts) > 3 {
+			http.NotFound(w, r)
+			return
+		}
+		token := parts[0]
+
+		session, err := db.AuthenticateToken(token)
+		if err == model.ErrNoToken {
+			http.Error(w, "Invalid feed token", http.StatusUnauthorized)
+			return
+		} else if err != nil {
+			http.Error(w, fmt.Sprintf("%v", err), http.StatusInternalServerError)
+			return
+		} else if !session.HasScope(model.ScopeFeed) {
+			// Other tokens would leak into podcast apps and logs.
+			http.Error(w, "Only tokens with the feed scope are accepted", http.StatusForbidden)
+			return
+		}
+		p := mailboxes.Permissions(session.User.Mailboxes)
+
+		if len(parts) == 3 && parts[1] == "audio" && strings.HasSuffix(parts[2], ".mp3") {
+			id, err := strconv.Atoi(strings.TrimSuffix(parts[2], ".mp3"))
+			if err != nil {
+				http.NotFound(w, r)
+				return
+			}
+			voicemail, err := db.GetVoicemail(id)
+			if err == model.ErrNoVoicemail || (err == nil && !p.Allows(voicemail.Called)) {
+				http.NotFound(w, r)
+				return
+			} else if err != nil {
+				http.Error(w, fmt.Sprintf("%v", err), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "audio/mpeg")
+			http.ServeFile(w, r, path.Join(voicemailDir, path.Base(voicemail.VoicemailPath)))
+			return
+		}
+
+		filter := model.Filter{Permissions: &p}
+		title := "Anrufbeantworter"
+		link := baseURL(r, publicURL) + "/"
+		if len(parts) == 3 {
+			mailbox, ok := mailboxes.Get(parts[1])
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			mailboxFilter := mailboxes.Filter(mailbox)
+			filter.Numbers = mailboxFilter.Numbers
+			filter.ExcludeNumbers = mailboxFilter.ExcludeNumbers
+			title += " – " + mailbox.Title
+			link += "?mailbox=" + mailbox.Name
+		}
+
+		page, err := db.GetPage(model.Query{Folder: model.Inbox, Filter: filter, Limit: feedLimit})
+		if err != nil {
+			http.Error(w, fmt.Sprintf("%v", err), http.StatusInternalServerError)
+			return
+		}
+		items := feedItems(page.Voicemails, mailboxes, voicemailDir, baseURL(r, publicURL), token)
+
+		switch parts[len(parts)-1] {
+		case "rss.xml":
+			err = writeRSS(w, title, link, items)
+		case "atom.xml":
+			id := "urn:voicemail:feed"
+			if len(parts) == 3 {
+				id += ":" + parts[1]
+			}
+			err = writeAtom(w, id, title, link, baseURL(r, publicURL)+r.URL.Path, items)
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			logger.Warn("Unable to write feed", "remote", r.RemoteAddr, "error", err)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package web
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"../model"
+)
+
+// Check tests whether a dependency of the service works.
+type Check func() error
+
+// checkTimeout limits how long a check may take.
+const checkTimeout = 5 * time.Second
+
+// health is the result of the checks of /healthz or /readyz.
+type health struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// runChecks runs the checks concurrently.  Checks that take longer
+// than checkTimeout fail.
+func runChecks(checks map[string]Check) health {
+	type result struct {
+		name string
+		err  error
+	}
+	// Late results are discarded.
+	results := make(chan result, len(checks))
+	for name, check := range checks {
+		go func(name string, check Check) {
+			results <- result{name, check()}
+		}(name, check)
+	}
+
+	h := health{Status: "ok", Checks: map[string]string{}}
+	timeout := time.After(checkTimeout)
+	for len(h.Checks) < len(checks) {
+		select {
+		case r := <-results:
+			h.Checks[r.name] = "ok"
+			if r.err != nil {
+				h.Checks[r.name] = r.err.Error()
+			}
+		case <-timeout:
+			for name := range checks {
+				if _, ok := h.Checks[name]; !ok {
+					h.Checks[name] = "timed out"
+				}
+			}
+		}
+	}
+	for _, status := range h.Checks {
+		if status != "ok" {
+			h.Status = "failed"
+		}
+	}
+	return h
+}
+
+// healthHandler answers with 200 if all checks pass, otherwise with 503.
+func healthHandler(checks map[string]Check) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h := runChecks(checks)
+		status := http.StatusOK
+		if h.Status != "ok" {
+			names := []string{}
+			for name, result := range h.Checks {
+				if result != "ok" {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+			logger.Warn("Health check failed", "path", r.URL.Path, "checks", names)
+			status = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		writeJSON(w, status, h)
+	}
+}
+
+// registerHealth adds /healthz, which checks that the database answers,
+// and /readyz, which runs the given checks as well.
+func registerHealth(mux *http.ServeMux, db model.Database, checks map[string]Check) {
+	ping := func() error {
+		return db.Ping(checkTimeout)
+	}
+	ready := map[string]Check{"database": ping}
+	for name, check := range checks {
+		ready[name] = check
+	}
+	mux.HandleFunc("/healthz", healthHandler(map[string]Check{"database": ping}))
+	mux.HandleFunc("/readyz", healthHandler(ready))
+}
@@ -0,0 +1,66 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"../metrics"
+)
+
+var (
+	httpRequests = metrics.NewCounter("voicemail_http_requests_total",
+		"HTTP requests by method and status code.", "method", "code")
+	httpDuration = metrics.NewHistogram("voicemail_http_request_duration_seconds",
+		"Time to answer HTTP requests, without event streams.", metrics.DurationBuckets)
+)
+
+// statusRecorder remembers the status code of a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush keeps event streams working.
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// instrument counts the requests handled by next.
+func instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+
+		if recorder.status == 0 {
+			recorder.status = http.StatusOK
+		}
+		method := r.Method
+		switch method {
+		case "GET", "HEAD", "POST", "DELETE":
+		default:
+			method = "OTHER"
+		}
+		httpRequests.Inc(method, strconv.Itoa(recorder.status))
+		if r.URL.Path != "/events" {
+			httpDuration.Observe(time.Since(start).Seconds())
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"../model"
+)
+
+// Tokens is the data of the API token page.
+type Tokens struct {
+	User   string
+	CSRF   string
+	Tokens []model.Token
+	Scopes []model.Scope
+	// NewToken is the secret of a token that was just created.  It
+	// is only shown once.
+	NewToken     string
+	NewTokenName string
+	Error        string
+}
+
+// tokensHandler lists the API tokens of the user and creates new ones.
+func tokensHandler(db model.Database) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		session := currentSession(r)
+		page := Tokens{
+			User:   session.User.Name,
+			CSRF:   session.CSRFToken,
+			Scopes: model.TokenScopes,
+		}
+
+		if r.Method == "POST" {
+			scope, err := model.ParseScopes([]string{r.FormValue("scope")})
+			if err == nil {
+				var token model.Token
+				token, page.NewToken, err = db.CreateToken(session.User.Name, r.FormValue("name"), scope)
+				page.NewTokenName = token.Name
+			}
+			if err == model.ErrInvalidScope || err == model.ErrInvalidTokenName {
+				page.Error = "Bitte einen Namen und eine Berechtigung angeben."
+				w.WriteHeader(http.StatusBadRequest)
+			} else if err != nil {
+				http.Error(w, fmt.Sprintf("%v", err), http.StatusInternalServerError)
+				return
+			} else {
+				logger.Info("API token created", "user", session.User.Name, "token", page.NewTokenName)
+			}
+		} else if r.Method != "GET" && r.Method != "HEAD" {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var err error
+		if page.Tokens, err = db.GetTokens(session.User.Name); err != nil {
+			http.Error(w, fmt.Sprintf("%v", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		if err := rootTemplate.ExecuteTemplate(w, "tokens", page); err != nil {
+			http.Error(w, fmt.Sprintf("%v", err), http.StatusInternalServerError)
+		}
+	}
+}
+
+// revokeTokenHandler deletes one of the user's API tokens.
+func revokeTokenHandler(db model.Database) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		id, err := strconv.Atoi(r.FormValue("id"))
+		if err != nil {
+			http.Error(w, "Invalid id", http.StatusBadRequest)
+			return
+		}
+
+		user := currentSession(r).User.Name
+		if err := db.RevokeToken(id, user); err == model.ErrNoToken {
+			http.NotFound(w, r)
+			return
+		} else if err != nil {
+			http.Error(w, fmt.Sprintf("%v", err), http.StatusInternalServerError)
+			return
+		}
+
+		logger.Info("API token revoked", "user", user, "token", id)
+		http.Redirect(w, r, "/tokens", http.StatusSeeOther)
+	}
+}
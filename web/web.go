@@ -3,54 +3,237 @@ package web
 import (
 	"fmt"
 	"html/template"
-	"log"
 	"math"
-	"net"
 	"net/http"
+	"net/url"
 	"path"
+	"strconv"
+	"strings"
 	"time"
 
+	"../metrics"
 	"../model"
 	. "../utils"
 
 	"../web/assets"
 )
 
-var rootTemplate *template.Template
-var logger *log.Logger = Logger("web")
+var rootTemplate = template.Must(template.New("root").
+	Funcs(template.FuncMap{"join": strings.Join}).Parse(string(app_html())))
+var logger = Logger("web")
 
 func isNewMessage(t time.Time) bool {
 	// All messages that are 48 hours old are new messages
 	return math.Abs(time.Now().Sub(t).Hours()) < 48
 }
 
+// Row is a voicemail as shown in the web interface.
+type Row struct {
+	model.Voicemail
+	CSRF string
+}
+
 type Group struct {
-	New []model.Voicemail
-	Old []model.Voicemail
+	User   string
+	CSRF   string
+	Folder string
+	// Mailbox is the name of the selected mailbox, empty for all.
+	Mailbox   string
+	Mailboxes model.Mailboxes
+	// Form holds the search parameters.
+	Form url.Values
+	New  []Row
+	Old  []Row
+	// Older and Newer link to the adjacent pages, if any.
+	Older string
+	Newer string
+}
+
+var folders = map[string]model.Folder{
+	"inbox":   model.Inbox,
+	"archive": model.Archive,
+	"trash":   model.Trash,
+}
+
+func pageURL(query url.Values, direction string, cursor model.Cursor) string {
+	v := url.Values{}
+	for key, values := range query {
+		if key != "before" && key != "after" {
+			v[key] = values
+		}
+	}
+	v.Set(direction, string(cursor))
+	return "/?" + v.Encode()
 }
 
-func rootHandler(db model.Database, limit int) func(http.ResponseWriter, *http.Request) {
+var readStates = map[string]model.ReadState{
+	"":       model.AnyReadState,
+	"read":   model.OnlyRead,
+	"unread": model.OnlyUnread,
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	seconds, err := strconv.Atoi(s)
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("invalid duration: %q", s)
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation("2006-01-02", s, time.Local)
+}
+
+// parseFilter reads the search parameters of the web interface.
+func parseFilter(query url.Values) (model.Filter, error) {
+	var err error
+	filter := model.Filter{
+		Text:   model.MatchWords(query.Get("q")),
+		Caller: query.Get("caller"),
+		Called: query.Get("called"),
+		Tags:   model.ParseTags(query.Get("tags")),
+	}
+
+	if filter.From, err = parseDate(query.Get("from")); err != nil {
+		return filter, err
+	}
+	if filter.Until, err = parseDate(query.Get("until")); err != nil {
+		return filter, err
+	}
+	if !filter.Until.IsZero() {
+		// The until date is inclusive in the web interface.
+		filter.Until = filter.Until.AddDate(0, 0, 1)
+	}
+
+	if filter.MinDuration, err = parseDuration(query.Get("min-duration")); err != nil {
+		return filter, err
+	}
+	if filter.MaxDuration, err = parseDuration(query.Get("max-duration")); err != nil {
+		return filter, err
+	}
+
+	var ok bool
+	if filter.Read, ok = readStates[query.Get("read")]; !ok {
+		return filter, fmt.Errorf("invalid read state: %q", query.Get("read"))
+	}
+
+	return filter, nil
+}
+
+// parseQuery reads the folder, mailbox, search and paging parameters
+// shared by the web interface and the API.  The result only selects
+// voicemails the user may access.
+func parseQuery(query url.Values, mailboxes model.Mailboxes, p model.Permissions, limit int) (model.Query, error) {
+	folderName := query.Get("folder")
+	if folderName == "" {
+		folderName = "inbox"
+	}
+	folder, ok := folders[folderName]
+	if !ok {
+		return model.Query{}, fmt.Errorf("unknown folder: %q", folderName)
+	}
+
+	filter, err := parseFilter(query)
+	if err != nil {
+		return model.Query{}, err
+	}
+	filter.Permissions = &p
+
+	if mailboxName := query.Get("mailbox"); mailboxName != "" {
+		mailbox, ok := mailboxes.Get(mailboxName)
+		if !ok {
+			return model.Query{}, fmt.Errorf("unknown mailbox: %q", mailboxName)
+		}
+		mailboxFilter := mailboxes.Filter(mailbox)
+		filter.Numbers = mailboxFilter.Numbers
+		filter.ExcludeNumbers = mailboxFilter.ExcludeNumbers
+	}
+
+	return model.Query{
+		Folder: folder,
+		Filter: filter,
+		Before: model.Cursor(query.Get("before")),
+		After:  model.Cursor(query.Get("after")),
+		Limit:  limit,
+	}, nil
+}
+
+// visibleMailboxes returns the mailboxes that the user may access
+// completely.
+func visibleMailboxes(mailboxes model.Mailboxes, p model.Permissions) model.Mailboxes {
+	visible := model.Mailboxes{}
+	for _, mailbox := range mailboxes {
+		if mailboxes.AllowsMailbox(p, mailbox) {
+			visible = append(visible, mailbox)
+		}
+	}
+	return visible
+}
+
+func rootHandler(db model.Database, limit int, mailboxes model.Mailboxes) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		newMessageGroup := []model.Voicemail{}
-		oldMessageGroup := []model.Voicemail{}
+		session := currentSession(r)
+		newMessageGroup := []Row{}
+		oldMessageGroup := []Row{}
+
+		query := r.URL.Query()
+		folderName := query.Get("folder")
+		if folderName == "" {
+			folderName = "inbox"
+		}
+		mailboxName := query.Get("mailbox")
 
-		voicemails, err := db.GetVoicemails(limit)
+		p := permissions(r, mailboxes)
+		q, err := parseQuery(query, mailboxes, p, limit)
 		if err != nil {
+			http.Error(w, fmt.Sprintf("%v", err), http.StatusBadRequest)
+			return
+		}
+
+		page, err := db.GetPage(q)
+		if err == model.ErrInvalidCursor {
+			http.Error(w, fmt.Sprintf("%v", err), http.StatusBadRequest)
+			return
+		} else if err != nil {
 			http.Error(w, fmt.Sprintf("%v", err), http.StatusInternalServerError)
 			return
 		}
 
-		for _, voicemail := range voicemails {
+		for _, voicemail := range page.Voicemails {
 			voicemail.VoicemailPath = path.Join("/voicemail", voicemail.VoicemailPath)
 
+			row := Row{voicemail, session.CSRFToken}
 			if isNewMessage(voicemail.Date) {
-				newMessageGroup = append(newMessageGroup, voicemail)
+				newMessageGroup = append(newMessageGroup, row)
 			} else {
-				oldMessageGroup = append(oldMessageGroup, voicemail)
+				oldMessageGroup = append(oldMessageGroup, row)
 			}
 		}
 
-		err = rootTemplate.ExecuteTemplate(w, "calls", Group{newMessageGroup, oldMessageGroup})
+		group := Group{
+			User:      session.User.Name,
+			CSRF:      session.CSRFToken,
+			Folder:    folderName,
+			Mailbox:   mailboxName,
+			Mailboxes: visibleMailboxes(mailboxes, p),
+			Form:      query,
+			New:       newMessageGroup,
+			Old:       oldMessageGroup,
+		}
+		if page.Older != "" {
+			group.Older = pageURL(query, "before", page.Older)
+		}
+		if page.Newer != "" {
+			group.Newer = pageURL(query, "after", page.Newer)
+		}
+
+		err = rootTemplate.ExecuteTemplate(w, "calls", group)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("%v", err), http.StatusInternalServerError)
 			return
@@ -58,6 +241,125 @@ func rootHandler(db model.Database, limit int) func(http.ResponseWriter, *http.R
 	}
 }
 
+// redirectBack sends the browser back to the page an action was
+// submitted from.
+func redirectBack(w http.ResponseWriter, r *http.Request) {
+	target := "/"
+	if referer, err := url.Parse(r.Referer()); err == nil && referer.Path != "" {
+		target = referer.RequestURI()
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
+
+// requestedVoicemail returns the voicemail with the id given in the
+// request's form if the user may access it.  Otherwise it responds
+// with an error and returns false.
+func requestedVoicemail(db model.Database, mailboxes model.Mailboxes, w http.ResponseWriter, r *http.Request) (model.Voicemail, bool) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return model.Voicemail{}, false
+	}
+
+	id, err := strconv.Atoi(r.FormValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid voicemail id", http.StatusBadRequest)
+		return model.Voicemail{}, false
+	}
+
+	voicemail, err := db.GetVoicemail(id)
+	if err == model.ErrNoVoicemail || (err == nil && !permissions(r, mailboxes).Allows(voicemail.Called)) {
+		http.NotFound(w, r)
+		return model.Voicemail{}, false
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("%v", err), http.StatusInternalServerError)
+		return model.Voicemail{}, false
+	}
+
+	return voicemail, true
+}
+
+func actionHandler(db model.Database, mailboxes model.Mailboxes, action func(id int) error) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		voicemail, ok := requestedVoicemail(db, mailboxes, w, r)
+		if !ok {
+			return
+		}
+
+		if err := action(voicemail.Id); err == model.ErrNoVoicemail {
+			http.NotFound(w, r)
+			return
+		} else if err != nil {
+			http.Error(w, fmt.Sprintf("%v", err), http.StatusInternalServerError)
+			return
+		}
+
+		if r.Header.Get("X-Requested-With") == "XMLHttpRequest" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		redirectBack(w, r)
+	}
+}
+
+func editHandler(db model.Database, mailboxes model.Mailboxes) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		voicemail, ok := requestedVoicemail(db, mailboxes, w, r)
+		if !ok {
+			return
+		}
+
+		err := db.SetNotes(voicemail.Id, r.FormValue("notes"))
+		if err == nil {
+			err = db.SetTags(voicemail.Id, model.ParseTags(r.FormValue("tags")))
+		}
+		if err == model.ErrNoVoicemail {
+			http.NotFound(w, r)
+			return
+		} else if err != nil {
+			http.Error(w, fmt.Sprintf("%v", err), http.StatusInternalServerError)
+			return
+		}
+
+		redirectBack(w, r)
+	}
+}
+
+func emptyTrashHandler(db model.Database, mailboxes model.Mailboxes) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		p := permissions(r, mailboxes)
+		if _, err := db.EmptyTrash(model.Filter{Permissions: &p}); err != nil {
+			http.Error(w, fmt.Sprintf("%v", err), http.StatusInternalServerError)
+			return
+		}
+
+		redirectBack(w, r)
+	}
+}
+
+// voicemailFileHandler serves the audio files of the voicemails the
+// user may access.
+func voicemailFileHandler(db model.Database, mailboxes model.Mailboxes, voicemailDir string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := path.Base(path.Clean("/" + strings.TrimPrefix(r.URL.Path, "/voicemail/")))
+
+		voicemail, err := db.GetVoicemailByPath(name)
+		if err == model.ErrNoVoicemail || (err == nil && !permissions(r, mailboxes).Allows(voicemail.Called)) {
+			http.NotFound(w, r)
+			return
+		} else if err != nil {
+			http.Error(w, fmt.Sprintf("%v", err), http.StatusInternalServerError)
+			return
+		}
+
+		http.ServeFile(w, r, path.Join(voicemailDir, name))
+	}
+}
+
 func handleAsset(f func() []byte, t string) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", t)
@@ -65,27 +367,50 @@ func handleAsset(f func() []byte, t string) func(http.ResponseWriter, *http.Requ
 	}
 }
 
-func Serve(l net.Listener, db model.Database, voicemailDir string, limit int) {
-	rootTemplate = template.New("root")
-	_, err := rootTemplate.Parse(string(app_html()))
-	if err != nil {
-		panic(err)
-	}
+// Handler returns the handler for the web interface and the API.
+// publicURL is the base URL of links that leave the browser, e.g. in
+// feeds.  If empty, it is derived from the request.
+func Handler(db model.Database, voicemailDir string, limit int, mailboxes model.Mailboxes, publicURL string,
+	checks map[string]Check) http.Handler {
+	mux := http.NewServeMux()
 
-	http.Handle("/voicemail/", http.StripPrefix("/voicemail/",
-		http.FileServer(http.Dir(voicemailDir))))
+	mux.HandleFunc("/voicemail/", voicemailFileHandler(db, mailboxes, voicemailDir))
 
-	http.HandleFunc("/js/zepto.min.js",
+	mux.HandleFunc("/js/zepto.min.js",
 		handleAsset(assets.Zepto_min_js, "text/javascript"))
-	http.HandleFunc("/css", handleAsset(assets.Bootstrap_combined_min_css, "text/css"))
-	http.HandleFunc("/img/glyphicons-halflings.png",
+	mux.HandleFunc("/css", handleAsset(assets.Bootstrap_combined_min_css, "text/css"))
+	mux.HandleFunc("/img/glyphicons-halflings.png",
 		handleAsset(assets.Glyphicons_halflings_png, "image/png"))
-	http.HandleFunc("/img/glyphicons-halflings-white.png",
+	mux.HandleFunc("/img/glyphicons-halflings-white.png",
 		handleAsset(assets.Glyphicons_halflings_white_png, "image/png"))
-	http.HandleFunc("/img/apple-touch-icon.png",
+	mux.HandleFunc("/img/apple-touch-icon.png",
 		handleAsset(assets.Apple_touch_icon_png, "image/png"))
 
-	http.HandleFunc("/", rootHandler(db, limit))
+	mux.HandleFunc("/archive", actionHandler(db, mailboxes, db.ArchiveVoicemail))
+	mux.HandleFunc("/unarchive", actionHandler(db, mailboxes, db.UnarchiveVoicemail))
+	mux.HandleFunc("/delete", actionHandler(db, mailboxes, db.DeleteVoicemail))
+	mux.HandleFunc("/restore", actionHandler(db, mailboxes, db.RestoreVoicemail))
+	mux.HandleFunc("/purge", actionHandler(db, mailboxes, db.PurgeVoicemail))
+	mux.HandleFunc("/star", actionHandler(db, mailboxes, db.StarVoicemail))
+	mux.HandleFunc("/unstar", actionHandler(db, mailboxes, db.UnstarVoicemail))
+	mux.HandleFunc("/mark-read", actionHandler(db, mailboxes, db.MarkRead))
+	mux.HandleFunc("/mark-unread", actionHandler(db, mailboxes, db.MarkUnread))
+	mux.HandleFunc("/edit", editHandler(db, mailboxes))
+	mux.HandleFunc("/empty-trash", emptyTrashHandler(db, mailboxes))
+
+	registerAPI(mux, db, voicemailDir, limit, mailboxes)
+
+	mux.HandleFunc("/events", eventsHandler(db, mailboxes))
+	mux.Handle("/metrics", metrics.Handler())
+	registerHealth(mux, db, checks)
+	mux.HandleFunc(feedPrefix, feedHandler(db, voicemailDir, mailboxes, publicURL))
+
+	mux.HandleFunc("/tokens", tokensHandler(db))
+	mux.HandleFunc("/tokens/revoke", revokeTokenHandler(db))
+
+	mux.HandleFunc("/login", loginHandler(db))
+	mux.HandleFunc("/logout", logoutHandler(db))
+	mux.HandleFunc("/", rootHandler(db, limit, mailboxes))
 
-	logger.Fatal(http.Serve(l, nil))
+	return instrument(requireLogin(db, mux))
 }